@@ -0,0 +1,127 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"strings"
+)
+
+// Several validation errors, in the order they were found.
+//
+// Errors may contain other aggregates, such as nested Errors, the result of
+// errors.Join, or PropertyErrors wrapping either. They are flattened when
+// rendered or counted, so an Errors always behaves as one flat list of
+// leaves. Plain append can be used to build an Errors; a Collector can be
+// given a capacity hint with Grow.
+type Errors []error
+
+// The messages of all errors joined by "; ".
+func (errs Errors) Error() string {
+	return errs.Join("; ", "")
+}
+
+// The messages of all errors, each preceded by prefix, joined by sep.
+//
+//	errs.Join("\n", "  - ") // a bulleted list, one error per line
+func (errs Errors) Join(sep, prefix string) string {
+	flat := errs.Flatten()
+	msgs := make([]string, len(flat))
+	for i, err := range flat {
+		msgs[i] = prefix + err.Error()
+	}
+	return strings.Join(msgs, sep)
+}
+
+func (errs Errors) isValidationError() {}
+
+// The errors in errs, for errors.Is and errors.As.
+func (errs Errors) Unwrap() []error {
+	return errs
+}
+
+// Nil if errs is empty or holds only nil errors, errs otherwise. Collecting
+// functions should return errs.Err() so that finding no errors yields a nil
+// error, and a literal Errors{check1, check2}.Err() is nil when every check
+// passed.
+func (errs Errors) Err() error {
+	for _, err := range errs {
+		if err != nil {
+			return errs
+		}
+	}
+	return nil
+}
+
+// The number of leaf errors in errs, counting those of nested aggregates
+// (see DistinctFields).
+func (errs Errors) Len() int {
+	return len(errs.Flatten())
+}
+
+// The number of distinct paths among the leaves of errs. It is less than
+// Len() when a property failed more than one check.
+func (errs Errors) DistinctFields() int {
+	return len(InvalidFields(errs))
+}
+
+// The leaf errors of errs, with the members of nested aggregates lifted
+// into a single list. A PropertyError wrapping an aggregate becomes one
+// PropertyError per member, each with the full path, and members validated
+// in a context (see VIn) each keep the context.
+func (errs Errors) Flatten() Errors {
+	var flat Errors
+	for _, err := range errs {
+		flat = append(flat, flatten(err)...)
+	}
+	return flat
+}
+
+// The only leaf of err, with its full path, if err has exactly one leaf, and
+// err itself otherwise. A lone failure collected into an aggregate then
+// comes back as a bare PropertyError, so type switches on PropertyError
+// keep working; two or more failures stay aggregated.
+//
+//	err := UnwrapSingle(Errors{Property("Bar", bar)}) // a PropertyError
+func UnwrapSingle(err error) error {
+	if flat := flatten(err); len(flat) == 1 {
+		return flat[0]
+	}
+	return err
+}
+
+func flatten(err error) []error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case PropertyError:
+		switch e.err.(type) {
+		case PropertyError, contextError, multiError:
+		default:
+			return []error{e}
+		}
+		inner := flatten(e.err)
+		flat := make([]error, len(inner))
+		for i, child := range inner {
+			e.err = child
+			flat[i] = e
+		}
+		return flat
+	case contextError:
+		inner := flatten(e.err)
+		flat := make([]error, len(inner))
+		for i, child := range inner {
+			e.err = child
+			flat[i] = e
+		}
+		return flat
+	case multiError:
+		var flat []error
+		for _, child := range e.Unwrap() {
+			flat = append(flat, flatten(child)...)
+		}
+		return flat
+	}
+	return []error{err}
+}
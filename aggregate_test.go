@@ -0,0 +1,100 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+	if err := errs.Err(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := (Errors{Property("A", testQux(1)), nil}).Err(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	errs = append(errs, Property("A", testQux(0)), errors.New("b"))
+	err := errs.Err()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if msg := err.Error(); msg != "A: qux; b" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	var perr PropertyError
+	if !errors.As(err, &perr) || perr.Property() != "A" {
+		t.Errorf("unexpected errors.As result %v", perr)
+	}
+}
+
+func TestErrorsJoin(t *testing.T) {
+	errs := Errors{
+		Property("A", testQux(0)),
+		Property("B", testQux(0)),
+		Property("C", testQux(0)),
+	}
+	if msg := errs.Error(); msg != "A: qux; B: qux; C: qux" {
+		t.Errorf("unexpected default message %q", msg)
+	}
+	if msg := errs.Join("\n", "  - "); msg != "  - A: qux\n  - B: qux\n  - C: qux" {
+		t.Errorf("unexpected bulleted message %q", msg)
+	}
+}
+
+func TestErrorsFlatten(t *testing.T) {
+	errs := Errors{
+		Errors{Property("A", testQux(0)), Errors{Property("B", testQux(0))}},
+		Property("Bars", testBars{testBar{0}, testBar{1}, testBar{0}}),
+		errors.Join(errors.New("c"), Labeled("Dee", "D", testQux(0))),
+	}
+	expect := "A: qux; B: qux; Bars[0].Baz: qux; Bars[2].Baz: qux; c; Dee: qux"
+	if msg := errs.Error(); msg != expect {
+		t.Errorf("expected %q got %q", expect, msg)
+	}
+	if n := errs.Len(); n != 6 {
+		t.Errorf("unexpected length %d", n)
+	}
+	flat := errs.Flatten()
+	if len(flat) != 6 {
+		t.Fatalf("unexpected flattened length %d", len(flat))
+	}
+	if prop := flat[3].(PropertyError).Property(); prop != "Bars[2].Baz" {
+		t.Errorf("unexpected property %q", prop)
+	}
+	if msg := errs.Join("\n", "- "); msg != "- A: qux\n- B: qux\n- Bars[0].Baz: qux\n- Bars[2].Baz: qux\n- c\n- Dee: qux" {
+		t.Errorf("unexpected joined message %q", msg)
+	}
+}
+
+func TestErrorsDistinctFields(t *testing.T) {
+	errs := Errors{
+		NewPropertyError("Password", errors.New("too short")),
+		NewPropertyError("Password", errors.New("no digits")),
+		NewPropertyError("Email", errors.New("required")),
+	}
+	if n := errs.Len(); n != 3 {
+		t.Errorf("unexpected length %d", n)
+	}
+	if n := errs.DistinctFields(); n != 2 {
+		t.Errorf("unexpected distinct fields %d", n)
+	}
+}
+
+func TestUnwrapSingle(t *testing.T) {
+	err := UnwrapSingle(Errors{nil, Errors{Property("Bar", testBar{0})}})
+	perr, ok := err.(PropertyError)
+	if !ok || perr.Error() != "Bar.Baz: qux" {
+		t.Errorf("expected a PropertyError got %#v", err)
+	}
+	err = UnwrapSingle(Errors{Property("Bar", testBar{0}), Property("Qux", testQux(0))})
+	if _, ok := err.(Errors); !ok || err.Error() != "Bar.Baz: qux; Qux: qux" {
+		t.Errorf("expected an aggregate got %#v", err)
+	}
+	if err := UnwrapSingle(nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
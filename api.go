@@ -0,0 +1,208 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Implemented by leaf errors that carry a machine readable error code.
+type Coder interface {
+	Code() string
+}
+
+// The code reported for leaf errors that do not implement Coder.
+const DefaultCode = "invalid"
+
+// The code of a leaf error.
+func codeOf(leaf error) string {
+	switch leaf.(type) {
+	case Coder:
+		return leaf.(Coder).Code()
+	}
+	return DefaultCode
+}
+
+type apiError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// Serialize the leaves of err as an API error response.
+//
+//	{"errors":[{"field":"Bars[1].Baz","code":"invalid","message":"qux"}]}
+//
+// Codes come from leaf errors implementing Coder, or DefaultCode otherwise.
+// Leaves with a hint (see Hinted) also have a "hint" member.
+func MarshalAPIErrors(err error) ([]byte, error) {
+	resp := struct {
+		Errors []apiError `json:"errors"`
+	}{[]apiError{}}
+	eachLeaf(err, func(path string, leaf error) {
+		resp.Errors = append(resp.Errors, apiError{path, codeOf(leaf), leafMessage(leaf), hintOf(leaf)})
+	})
+	return json.Marshal(resp)
+}
+
+// Replace the code of every leaf in err whose code is not allowed with
+// DefaultCode, keeping leaf messages and paths. This keeps unknown codes
+// from third-party validators out of API responses.
+func EnforceCodes(err error, allowed map[string]bool) error {
+	return mapLeaves(err, func(path string, leaf error) error {
+		if code := codeOf(leaf); code != DefaultCode && !allowed[code] {
+			return recodedError{leaf, DefaultCode}
+		}
+		return leaf
+	})
+}
+
+// A leaf error with its code overridden.
+type recodedError struct {
+	err  error
+	code string
+}
+
+func (err recodedError) Error() string { return err.err.Error() }
+func (err recodedError) Code() string  { return err.code }
+func (err recodedError) Unwrap() error { return err.err }
+
+type fieldMessage struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Write the leaves of err to w as a JSON array of {"field","message"}
+// objects, one leaf at a time, without building the whole document in
+// memory.
+func EncodeJSON(w io.Writer, err error) error {
+	if _, werr := io.WriteString(w, "["); werr != nil {
+		return werr
+	}
+	enc := json.NewEncoder(w)
+	var werr error
+	sep := ""
+	eachLeaf(err, func(path string, leaf error) {
+		if werr != nil {
+			return
+		}
+		if _, werr = io.WriteString(w, sep); werr == nil {
+			werr = enc.Encode(fieldMessage{path, leafMessage(leaf)})
+		}
+		sep = ","
+	})
+	if werr != nil {
+		return werr
+	}
+	_, werr = io.WriteString(w, "]\n")
+	return werr
+}
+
+// A field violation shaped like google.rpc.BadRequest.FieldViolation.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// The leaves of err as gRPC field violations, in order, with each leaf's
+// path as Field and its message as Description.
+//
+//	FieldViolations(err) // [{Bars[1].Baz qux} {Email required}]
+func FieldViolations(err error) []FieldViolation {
+	var violations []FieldViolation
+	eachLeaf(err, func(path string, leaf error) {
+		violations = append(violations, FieldViolation{path, leafMessage(leaf)})
+	})
+	return violations
+}
+
+// A leaf failure with its full path.
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+// At most n leaves of err, sorted by path, for responses that only show the
+// first few problems; n of 0 or less means no limit. Leaves with equal
+// paths keep their order in err.
+//
+//	Top(err, 2) // [{Bars[0].Baz qux} {Bars[2].Baz qux}]
+func Top(err error, n int) []FieldError {
+	var fields []FieldError
+	eachLeaf(err, func(path string, leaf error) {
+		fields = append(fields, FieldError{path, leafMessage(leaf)})
+	})
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	if n > 0 && len(fields) > n {
+		fields = fields[:n]
+	}
+	return fields
+}
+
+// The messages of the leaves of err keyed by the RFC 6901 JSON Pointer of
+// each leaf, like "/Bars/1/Baz", for JSON Patch style clients. Each property
+// name and index is one reference token, with "~" escaped as "~0" and "/"
+// as "~1"; a leaf without a path has the empty pointer, which refers to the
+// whole document. The messages of leaves sharing a pointer, including the
+// key and value failures of one map entry (see Entries), are joined by "; "
+// in order.
+//
+//	PointerErrors(err) // map[/Bars/1/Baz:qux /Env/a~1b:required]
+func PointerErrors(err error) map[string]string {
+	msgs := make(map[string]string)
+	walkPointers("", err, func(pointer string, leaf error) {
+		if msg, ok := msgs[pointer]; ok {
+			msgs[pointer] = msg + "; " + leafMessage(leaf)
+		} else {
+			msgs[pointer] = leafMessage(leaf)
+		}
+	})
+	return msgs
+}
+
+func walkPointers(prefix string, err error, fn func(pointer string, leaf error)) {
+	switch e := err.(type) {
+	case nil:
+	case PropertyError:
+		if e.property != "" {
+			prefix += "/" + escapePointer(e.property)
+		}
+		if e.index != nil {
+			prefix += "/" + escapePointer(pointerToken(e.index))
+		}
+		walkPointers(prefix, e.err, fn)
+	case contextError:
+		walkPointers(prefix, e.err, fn)
+	case multiError:
+		for _, child := range e.Unwrap() {
+			walkPointers(prefix, child, fn)
+		}
+	default:
+		fn(prefix, err)
+	}
+}
+
+// The unescaped reference token of an index.
+func pointerToken(index interface{}) string {
+	switch index.(type) {
+	case mapKey:
+		return pointerToken(index.(mapKey).key)
+	case wireSegment:
+		return index.(wireSegment).token
+	case PathSegment:
+		return index.(PathSegment).Segment()
+	}
+	return fmt.Sprint(index)
+}
+
+// Escape a reference token of a JSON Pointer (RFC 6901).
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
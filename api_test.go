@@ -0,0 +1,197 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testCodedError struct{ code, msg string }
+
+func (err testCodedError) Error() string { return err.msg }
+func (err testCodedError) Code() string  { return err.code }
+
+func TestMarshalAPIErrors(t *testing.T) {
+	err := Errors{
+		Property("Bar", testBar{0}),
+		NewPropertyError("Email", testCodedError{"required", "is required"}),
+	}
+	p, e := MarshalAPIErrors(err)
+	if e != nil {
+		t.Fatal(e)
+	}
+	expect := `{"errors":[` +
+		`{"field":"Bar.Baz","code":"invalid","message":"qux"},` +
+		`{"field":"Email","code":"required","message":"is required"}]}`
+	if string(p) != expect {
+		t.Errorf("expected %s got %s", expect, p)
+	}
+	p, e = MarshalAPIErrors(nil)
+	if e != nil || string(p) != `{"errors":[]}` {
+		t.Errorf("unexpected result %s (%v)", p, e)
+	}
+}
+
+func TestEnforceCodes(t *testing.T) {
+	err := Errors{
+		NewPropertyError("Email", testCodedError{"required", "is required"}),
+		NewPropertyError("Name", testCodedError{"x-rogue", "bad name"}),
+		Property("Bar", testBar{0}),
+	}
+	err2 := EnforceCodes(err, map[string]bool{"required": true})
+	p, e := MarshalAPIErrors(err2)
+	if e != nil {
+		t.Fatal(e)
+	}
+	expect := `{"errors":[` +
+		`{"field":"Email","code":"required","message":"is required"},` +
+		`{"field":"Name","code":"invalid","message":"bad name"},` +
+		`{"field":"Bar.Baz","code":"invalid","message":"qux"}]}`
+	if string(p) != expect {
+		t.Errorf("expected %s got %s", expect, p)
+	}
+	if err2.Error() != err.Error() {
+		t.Errorf("unexpected message %q", err2)
+	}
+
+	warned := NewPropertyError("Name", Warn(testCodedError{"x-rogue", "bad name"}))
+	p, e = MarshalAPIErrors(warned)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if expect := `{"errors":[{"field":"Name","code":"x-rogue","message":"bad name"}]}`; string(p) != expect {
+		t.Errorf("expected %s got %s", expect, p)
+	}
+	p, e = MarshalAPIErrors(EnforceCodes(warned, map[string]bool{"required": true}))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if expect := `{"errors":[{"field":"Name","code":"invalid","message":"bad name"}]}`; string(p) != expect {
+		t.Errorf("expected %s got %s", expect, p)
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	err := Errors{
+		Property("Bars", testBars{testBar{0}, testBar{1}, testBar{0}}),
+		NewPropertyError("Email", errors.New("required")),
+	}
+	var buf bytes.Buffer
+	if e := EncodeJSON(&buf, err); e != nil {
+		t.Fatal(e)
+	}
+	var decoded []map[string]string
+	if e := json.Unmarshal(buf.Bytes(), &decoded); e != nil {
+		t.Fatalf("%v: %s", e, buf.Bytes())
+	}
+	expect := []map[string]string{
+		{"field": "Bars[0].Baz", "message": "qux"},
+		{"field": "Bars[2].Baz", "message": "qux"},
+		{"field": "Email", "message": "required"},
+	}
+	if !reflect.DeepEqual(decoded, expect) {
+		t.Errorf("expected %v got %v", expect, decoded)
+	}
+	buf.Reset()
+	if e := EncodeJSON(&buf, nil); e != nil || buf.String() != "[]\n" {
+		t.Errorf("unexpected result %q (%v)", buf.String(), e)
+	}
+}
+
+func TestFieldViolations(t *testing.T) {
+	err := Errors{
+		Property("Bars", testBars{testBar{0}, testBar{1}, testBar{0}}),
+		NewPropertyError("Email", errors.New("required")),
+	}
+	expect := []FieldViolation{
+		{"Bars[0].Baz", "qux"},
+		{"Bars[2].Baz", "qux"},
+		{"Email", "required"},
+	}
+	if violations := FieldViolations(err); !reflect.DeepEqual(violations, expect) {
+		t.Errorf("expected %v got %v", expect, violations)
+	}
+	if violations := FieldViolations(nil); violations != nil {
+		t.Errorf("unexpected violations %v", violations)
+	}
+}
+
+func TestTop(t *testing.T) {
+	err := Errors{
+		NewPropertyError("Email", errors.New("required")),
+		Property("Bars", testBars{testBar{1}, testBar{0}, testBar{0}}),
+		NewPropertyError("Age", errors.New("too young")),
+		NewPropertyError("Name", errors.New("required")),
+	}
+	expect := []FieldError{
+		{"Age", "too young"},
+		{"Bars[1].Baz", "qux"},
+	}
+	if top := Top(err, 2); !reflect.DeepEqual(top, expect) {
+		t.Errorf("expected %v got %v", expect, top)
+	}
+	if top := Top(err, 10); len(top) != 5 {
+		t.Errorf("expected all 5 leaves got %v", top)
+	}
+	for _, n := range []int{0, -1} {
+		if top := Top(err, n); len(top) != 5 {
+			t.Errorf("%d: expected all 5 leaves got %v", n, top)
+		}
+	}
+	if top := Top(nil, 2); top != nil {
+		t.Errorf("unexpected leaves %v", top)
+	}
+}
+
+func TestAPIEmptyLeafMessage(t *testing.T) {
+	err := NewPropertyError("Name", errors.New(""))
+	p, e := MarshalAPIErrors(err)
+	if e != nil || string(p) != `{"errors":[{"field":"Name","code":"invalid","message":"invalid"}]}` {
+		t.Errorf("unexpected response %s (%v)", p, e)
+	}
+	var buf bytes.Buffer
+	if e := EncodeJSON(&buf, err); e != nil || buf.String() != `[{"field":"Name","message":"invalid"}`+"\n]\n" {
+		t.Errorf("unexpected result %q (%v)", buf.String(), e)
+	}
+	if v := FieldViolations(err); len(v) != 1 || v[0].Description != "invalid" {
+		t.Errorf("unexpected violations %v", v)
+	}
+	if top := Top(err, 1); len(top) != 1 || top[0].Message != "invalid" {
+		t.Errorf("unexpected leaves %v", top)
+	}
+}
+
+func TestPointerErrors(t *testing.T) {
+	env := map[string]string{"a/b": "", "ok": "x", "~c": ""}
+	err := Errors{
+		Property("Bars", testBars{testBar{1}, testBar{0}}),
+		Entries("Env", env, nil, func(k, v interface{}) error {
+			if v == "" {
+				return ErrRequired
+			}
+			return nil
+		}),
+		NewPropertyError("Email", errors.New("required")),
+		NewPropertyError("Email", errors.New("invalid domain")),
+		errors.New("plain"),
+	}
+	expect := map[string]string{
+		"/Bars/1/Baz": "qux",
+		"/Env/a~1b":   "required",
+		"/Env/~0c":    "required",
+		"/Email":      "required; invalid domain",
+		"":            "plain",
+	}
+	if ptrs := PointerErrors(err); !reflect.DeepEqual(ptrs, expect) {
+		t.Errorf("expected %q got %q", expect, ptrs)
+	}
+	if ptrs := PointerErrors(nil); len(ptrs) != 0 {
+		t.Errorf("unexpected pointers %q", ptrs)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"unicode/utf8"
+)
+
+// Require that b is valid UTF-8. The message gives the offset of the first
+// invalid byte.
+//
+//	UTF8([]byte("a\xffb")) // `invalid UTF-8 at byte 1`
+func UTF8(b []byte) error {
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size == 1 {
+			return fmt.Errorf("invalid UTF-8 at byte %d", i)
+		}
+		i += size
+	}
+	return nil
+}
+
+// Require that b is at most n bytes long.
+//
+//	MaxBytes([]byte("abc"), 2) // `must be at most 2 bytes (has 3)`
+func MaxBytes(b []byte, n int) error {
+	if len(b) > n {
+		return fmt.Errorf("must be at most %d bytes (has %d)", n, len(b))
+	}
+	return nil
+}
+
+// Require that b begins with prefix, like a file's magic number. The prefix
+// is quoted in the message.
+//
+//	HasPrefix([]byte("GIF89a"), []byte("\x89PNG")) // `missing prefix "\x89PNG"`
+func HasPrefix(b, prefix []byte) error {
+	if !bytes.HasPrefix(b, prefix) {
+		return fmt.Errorf("missing prefix %q", prefix)
+	}
+	return nil
+}
+
+// Require that expected is the CRC-32 (IEEE polynomial) checksum of data,
+// such as a Checksum field covering a Payload field. The failure is
+// attributed to property. Use ChecksumHash for another algorithm.
+//
+//	Checksum("Checksum", msg.Checksum, msg.Payload) // `Checksum: mismatch (expected 00000001, computed cbf43926)`
+func Checksum(property string, expected uint32, data []byte) error {
+	return ChecksumHash(property, expected, data, crc32.NewIEEE())
+}
+
+// Like Checksum with the 32-bit hash h, like crc32.New(crc32.MakeTable(crc32.Castagnoli))
+// or fnv.New32a(). h is reset before use.
+func ChecksumHash(property string, expected uint32, data []byte, h hash.Hash32) error {
+	h.Reset()
+	h.Write(data)
+	if sum := h.Sum32(); sum != expected {
+		return NewPropertyError(property, fmt.Errorf("mismatch (expected %08x, computed %08x)", expected, sum))
+	}
+	return nil
+}
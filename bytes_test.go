@@ -0,0 +1,53 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"hash/fnv"
+	"testing"
+)
+
+func TestBytes(t *testing.T) {
+	for _, test := range []struct {
+		err error
+		msg string
+	}{
+		{UTF8([]byte("héllo")), ""},
+		{UTF8([]byte("a\xffb")), `invalid UTF-8 at byte 1`},
+		{UTF8([]byte("ab\xe2\x82")), `invalid UTF-8 at byte 2`},
+		{MaxBytes([]byte("ab"), 2), ""},
+		{MaxBytes([]byte("abc"), 2), `must be at most 2 bytes (has 3)`},
+		{HasPrefix([]byte("\x89PNG\r\n"), []byte("\x89PNG")), ""},
+		{HasPrefix([]byte("GIF89a"), []byte("\x89PNG")), `missing prefix "\x89PNG"`},
+		{HasPrefix(nil, []byte("\x89PNG")), `missing prefix "\x89PNG"`},
+	} {
+		if test.msg == "" {
+			if test.err != nil {
+				t.Errorf("unexpected error %v", test.err)
+			}
+			continue
+		}
+		if test.err == nil || test.err.Error() != test.msg {
+			t.Errorf("expected %q got %v", test.msg, test.err)
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	data := []byte("123456789")
+	if err := Checksum("Checksum", 0xcbf43926, data); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := Checksum("Checksum", 1, data)
+	if err == nil || err.Error() != "Checksum: mismatch (expected 00000001, computed cbf43926)" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Checksum("Checksum", 0, nil); err != nil {
+		t.Errorf("empty data: unexpected error %v", err)
+	}
+	if err := ChecksumHash("Checksum", 0xbb86b11c, data, fnv.New32a()); err != nil {
+		t.Errorf("fnv: unexpected error %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+)
+
+// Memoizes V for values that recur unchanged, like configuration reloaded
+// with identical content. Results are keyed by the SHA-256 of the value's
+// type and %#v rendering, so only use a Cache for immutable values whose
+// validity depends on their content alone. Pointers render as addresses and
+// are keyed by identity, not by what they point to. When the cache holds
+// size results the least recently used one is evicted. A Cache is safe for
+// concurrent use.
+type Cache struct {
+	size    int
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[[sha256.Size]byte]*list.Element
+}
+
+type cacheEntry struct {
+	key [sha256.Size]byte
+	err error
+}
+
+// A cache holding at most size results. A size less than 1 disables caching.
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[[sha256.Size]byte]*list.Element),
+	}
+}
+
+// Like V but returns the cached result for a value equal to one already
+// validated.
+func (c *Cache) V(value interface{}) error {
+	if c.size < 1 {
+		return V(value)
+	}
+	key := sha256.Sum256([]byte(fmt.Sprintf("%T %#v", value, value)))
+	if err, ok := c.get(key); ok {
+		return err
+	}
+	err := V(value)
+	c.put(key, err)
+	return err
+}
+
+// The number of cached results.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *Cache) get(key [sha256.Size]byte) (error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		return elem.Value.(cacheEntry).err, true
+	}
+	return nil, false
+}
+
+func (c *Cache) put(key [sha256.Size]byte, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(cacheEntry{key, err})
+	if c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(cacheEntry).key)
+	}
+}
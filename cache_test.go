@@ -0,0 +1,54 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+)
+
+var testReloadCalls int
+
+type testReload struct {
+	Name string
+	Port int
+}
+
+func (c testReload) Validate() error {
+	testReloadCalls++
+	if c.Port < 1 {
+		return Invalid("port", c.Port)
+	}
+	return nil
+}
+
+func TestCache(t *testing.T) {
+	testReloadCalls = 0
+	cache := NewCache(2)
+	if err := cache.V(testReload{"a", 0}); err == nil {
+		t.Errorf("expected error")
+	}
+	if err := cache.V(testReload{"a", 0}); err == nil {
+		t.Errorf("expected cached error")
+	}
+	if testReloadCalls != 1 {
+		t.Errorf("equal value: expected 1 call got %d", testReloadCalls)
+	}
+	if err := cache.V(testReload{"a", 80}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if testReloadCalls != 2 {
+		t.Errorf("changed value: expected 2 calls got %d", testReloadCalls)
+	}
+
+	// testReload{"a", 0} is least recently used and gets evicted.
+	cache.V(testReload{"b", 80})
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 cached results got %d", cache.Len())
+	}
+	cache.V(testReload{"a", 0})
+	if testReloadCalls != 4 {
+		t.Errorf("evicted value: expected 4 calls got %d", testReloadCalls)
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Validate the keys and values of the map m. Key errors are reported at
+// property{key} and value errors at property[key]. Either function may be
+// nil to skip checking that part of the entries. All failures are collected,
+// in key order, into an Errors value. A nil m has no entries. Keys are
+// sorted unless m implements KeyOrderer; m may also be an OrderedMap
+// wrapping the map to validate.
+//
+//	Entries("Env", env, nil, func(k, v interface{}) error { ... }) // `Env["HOME"]: ...`
+func Entries(property string, m interface{}, keyFn func(k interface{}) error, valFn func(k, v interface{}) error) error {
+	if m == nil {
+		return nil
+	}
+	orderer, _ := m.(KeyOrderer)
+	if om, ok := m.(OrderedMap); ok {
+		m = om.Map()
+		if m == nil {
+			return nil
+		}
+	}
+	mval := reflect.ValueOf(m)
+	if mval.Kind() != reflect.Map {
+		panic(fmt.Sprintf("validate: Entries of non-map type %T", m))
+	}
+	var errs Errors
+	for _, key := range entryKeys(mval, orderer) {
+		k := key.Interface()
+		if keyFn != nil {
+			if err := keyFn(k); err != nil {
+				errs = append(errs, elementError(property, mapKey{k}, err))
+			}
+		}
+		if valFn != nil {
+			if err := valFn(k, mval.MapIndex(key).Interface()); err != nil {
+				errs = append(errs, elementError(property, k, err))
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// Require that the map m has every one of keys, reporting each missing key
+// at property[key] in the order given. Extra keys are allowed; reject them
+// with a key function passed to Entries. A nil m has no keys.
+//
+//	RequiredKeys("Regions", regions, "us-east", "eu-west") // `Regions["us-east"]: missing`
+func RequiredKeys(property string, m interface{}, keys ...interface{}) error {
+	mval := reflect.ValueOf(m)
+	if m != nil && mval.Kind() != reflect.Map {
+		panic(fmt.Sprintf("validate: RequiredKeys of non-map type %T", m))
+	}
+	var errs Errors
+	for _, k := range keys {
+		if m != nil {
+			key := reflect.ValueOf(k)
+			if !key.IsValid() || !key.Type().AssignableTo(mval.Type().Key()) {
+				panic(fmt.Sprintf("validate: RequiredKeys key of non-key type %T", k))
+			}
+			if mval.MapIndex(key).IsValid() {
+				continue
+			}
+		}
+		errs = append(errs, elementError(property, k, ErrMissing))
+	}
+	return errs.Err()
+}
+
+// Implemented by map types with an order of their own, such as insertion
+// order, so that Entries reports failures in that order. KeyOrder returns
+// keys of the map in order; keys it returns that are not in the map are
+// ignored, as are keys that cannot be compared, and keys of the map it omits
+// follow in sorted order.
+type KeyOrderer interface {
+	KeyOrder() []interface{}
+}
+
+// Implemented by types wrapping a map with an order of their own, such as a
+// struct holding a map and a slice of its keys. Entries validates the map
+// returned by Map in KeyOrder.
+type OrderedMap interface {
+	KeyOrderer
+	Map() interface{}
+}
+
+// Validate every element of children, collecting failures at property[i].
+// Children may call Children again from their own Validate() methods; the
+// paths compose, so a tree renders as `Root.Children[2].Children[0].Name`.
+//
+//	func (node *Node) Validate() error {
+//		if err := validator.PropertyRequired("Name", node.Name); err != nil {
+//			return err
+//		}
+//		return validator.Children("Children", node.Children)
+//	}
+func Children[T Interface](property string, children []T) error {
+	var errs Errors
+	for i, child := range children {
+		if err := V(child); err != nil {
+			errs = append(errs, elementError(property, i, err))
+		}
+	}
+	return errs.Err()
+}
+
+// Validate every non-nil element of s, collecting failures at property[i].
+// Nil elements are skipped.
+//
+//	EachPtr("Items", []*Item{valid, nil, invalid}) // `Items[2]: ...`
+func EachPtr[T Interface](property string, s []*T) error {
+	var errs Errors
+	for i, elem := range s {
+		if elem == nil {
+			continue
+		}
+		if err := V(*elem); err != nil {
+			errs = append(errs, elementError(property, i, err))
+		}
+	}
+	return errs.Err()
+}
+
+// Apply rule to every index in [0, n), collecting failures at property[i].
+// This suits slices of scalars that do not implement Interface.
+//
+//	EachFunc("Tags", len(tags), func(i int) error {
+//		return validate.IntString(tags[i], 1, 20)
+//	}) // `Tags[2]: Invalid not a number: "x"`
+func EachFunc(property string, n int, rule func(i int) error) error {
+	var errs Errors
+	for i := 0; i < n; i++ {
+		if err := rule(i); err != nil && err != Skipped {
+			errs = append(errs, elementError(property, i, err))
+		}
+	}
+	return errs.Err()
+}
+
+// Require that s is in ascending (non-decreasing) order. The first element
+// smaller than the one before it is reported.
+//
+//	Sorted("Events", []int{1, 2, 2, 5, 4}) // `Events[4]: out of order`
+func Sorted[T cmp.Ordered](property string, s []T) error {
+	return SortedFunc(property, s, cmp.Less[T])
+}
+
+// Like Sorted but s must be strictly increasing, so the first element equal
+// to the one before it is reported as a duplicate.
+//
+//	SortedStrict("Events", []int{1, 2, 2}) // `Events[2]: duplicate`
+func SortedStrict[T cmp.Ordered](property string, s []T) error {
+	for i := 1; i < len(s); i++ {
+		if s[i] == s[i-1] {
+			return elementError(property, i, errDuplicate)
+		}
+		if cmp.Less(s[i], s[i-1]) {
+			return elementError(property, i, errOutOfOrder)
+		}
+	}
+	return nil
+}
+
+// Like Sorted for types without a natural order, ordered by less.
+//
+//	SortedFunc("Events", events, func(a, b Event) bool { return a.At.Before(b.At) })
+func SortedFunc[T any](property string, s []T, less func(a, b T) bool) error {
+	for i := 1; i < len(s); i++ {
+		if less(s[i], s[i-1]) {
+			return elementError(property, i, errOutOfOrder)
+		}
+	}
+	return nil
+}
+
+// Require that the elements of values are distinct. The first element equal
+// to an earlier one is reported, naming the earlier index; later elements
+// are not checked.
+//
+//	Unique("Tags", []string{"a", "b", "a"}) // `Tags[2]: duplicate of [0]`
+func Unique[T comparable](property string, values []T) error {
+	return UniqueBy(property, values, func(v T) T { return v })
+}
+
+// Like Unique but elements are compared by the key extracted from each, as
+// for case-insensitive strings or structs identified by an ID field.
+//
+//	UniqueBy("Users", users, func(u User) int { return u.ID })
+func UniqueBy[T any, K comparable](property string, values []T, key func(T) K) error {
+	seen := make(map[K]int, len(values))
+	for i, v := range values {
+		k := key(v)
+		if j, ok := seen[k]; ok {
+			return elementError(property, i, fmt.Errorf("duplicate of [%d]", j))
+		}
+		seen[k] = i
+	}
+	return nil
+}
+
+// The error reported by Excludes for a forbidden value.
+var ErrForbidden = errors.New("forbidden")
+
+// Require that slice holds none of the forbidden values, as for a deny-list
+// of tags. Every occurrence of a forbidden value is reported at its index,
+// not just the first, so all of them can be fixed at once. The message does
+// not repeat the value, which may be untrusted input.
+//
+//	Excludes("Tags", []string{"a", "b", "admin"}, "admin", "root") // `Tags[2]: forbidden`
+func Excludes[T comparable](property string, slice []T, forbidden ...T) error {
+	deny := make(map[T]bool, len(forbidden))
+	for _, v := range forbidden {
+		deny[v] = true
+	}
+	var errs Errors
+	for i, v := range slice {
+		if deny[v] {
+			errs = append(errs, elementError(property, i, ErrForbidden))
+		}
+	}
+	return errs.Err()
+}
+
+// Apply rule to every pair of adjacent elements of s, in order. A failure is
+// attributed to the later element of the pair, at property[i] for the pair
+// s[i-1], s[i], and every failing pair is reported.
+//
+//	Pairwise("Events", events, func(prev, cur Event) error {
+//		if cur.At.Sub(prev.At) > time.Hour {
+//			return errors.New("gap too large")
+//		}
+//		return nil
+//	}) // `Events[3]: gap too large`
+func Pairwise[T any](property string, s []T, rule func(prev, cur T) error) error {
+	var errs Errors
+	for i := 1; i < len(s); i++ {
+		if err := rule(s[i-1], s[i]); err != nil && err != Skipped {
+			errs = append(errs, elementError(property, i, err))
+		}
+	}
+	return errs.Err()
+}
+
+var (
+	errOutOfOrder = errors.New("out of order")
+	errDuplicate  = errors.New("duplicate")
+)
+
+// Validate every element of a slice or array that is a window into a larger
+// one starting at offset. Failures are collected at property[offset+i], the
+// element's position in the larger slice. A nil slice has no elements.
+//
+//	EachFrom("Items", items[100:110], 100) // `Items[103]: ...`
+func EachFrom(property string, slice interface{}, offset int) error {
+	val := reflect.ValueOf(slice)
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Invalid:
+	default:
+		panic(fmt.Sprintf("validate: EachFrom of non-slice type %T", slice))
+	}
+	var errs Errors
+	for i := 0; val.IsValid() && i < val.Len(); i++ {
+		if err := V(val.Index(i).Interface()); err != nil {
+			errs = append(errs, elementError(property, offset+i, err))
+		}
+	}
+	return errs.Err()
+}
+
+// Require that the slice, array or map collection has at least n elements.
+// Nil and empty collections both have no elements.
+//
+//	MinItems("Tags", []string{"a", "b"}, 3) // `Tags: must have at least 3 items (has 2)`
+func MinItems(property string, collection interface{}, n int) error {
+	if size := collectionLen(collection); size < n {
+		return NewPropertyError(property, fmt.Errorf("must have at least %s (has %d)", items(n), size))
+	}
+	return nil
+}
+
+// Require that the slice, array or map collection has at most n elements.
+//
+//	MaxItems("Tags", []string{"a", "b"}, 1) // `Tags: must have at most 1 item (has 2)`
+func MaxItems(property string, collection interface{}, n int) error {
+	if size := collectionLen(collection); size > n {
+		return NewPropertyError(property, fmt.Errorf("must have at most %s (has %d)", items(n), size))
+	}
+	return nil
+}
+
+// Require that the slice, array or map collections a and b have the same
+// number of elements, such as paired arrays of labels and values. The error
+// is attributed to both properties. Like MinItems, SameLength panics if
+// either is not a collection.
+//
+//	SameLength("Labels", labels, "Values", values) // `Labels,Values: length mismatch (3 vs 2)`
+func SameLength(propertyA string, a interface{}, propertyB string, b interface{}) error {
+	if m, n := collectionLen(a), collectionLen(b); m != n {
+		return groupError([]string{propertyA, propertyB}, fmt.Errorf("length mismatch (%d vs %d)", m, n))
+	}
+	return nil
+}
+
+func items(n int) string {
+	if n == 1 {
+		return "1 item"
+	}
+	return fmt.Sprintf("%d items", n)
+}
+
+// The number of elements in a slice, array or map. Nil collections, typed
+// or not, are empty, so they fail MinItems like empty ones do.
+func collectionLen(collection interface{}) int {
+	val := reflect.ValueOf(collection)
+	switch val.Kind() {
+	case reflect.Invalid:
+		return 0
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return val.Len()
+	}
+	panic(fmt.Sprintf("validate: length of non-collection type %T", collection))
+}
+
+// The error for an invalid element of a collection property. The property
+// and index are stored in a single PropertyError, rendered the same as one
+// nested with Nest but with half the allocations. Collection helpers report
+// one error per invalid element, so this matters for large collections.
+func elementError(property string, index interface{}, err error) error {
+	return PropertyError{property: property, index: index, err: err}
+}
+
+// An index referring to a map key itself, rendered in braces.
+type mapKey struct {
+	key interface{}
+}
+
+func (k mapKey) Segment() string { return fmt.Sprintf("%#v", k.key) }
+
+// The keys of a map in the KeyOrder of orderer, if not nil, or else sorted.
+func entryKeys(m reflect.Value, orderer KeyOrderer) []reflect.Value {
+	if orderer == nil {
+		return sortedKeys(m)
+	}
+	var keys []reflect.Value
+	seen := make(map[interface{}]bool)
+	for _, k := range orderer.KeyOrder() {
+		key := reflect.ValueOf(k)
+		if !key.IsValid() || !key.Comparable() || !key.Type().AssignableTo(m.Type().Key()) || seen[k] {
+			continue
+		}
+		if m.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+			seen[k] = true
+		}
+	}
+	for _, key := range sortedKeys(m) {
+		if !seen[key.Interface()] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// The keys of a map in a deterministic order.
+func sortedKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return lessValue(keys[i], keys[j]) })
+	return keys
+}
+
+func lessValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	}
+	return fmt.Sprintf("%#v", a.Interface()) < fmt.Sprintf("%#v", b.Interface())
+}
@@ -0,0 +1,394 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEntries(t *testing.T) {
+	m := map[string]int{"ok": 1, "Bad Key": 2, "neg": -1}
+	keyFn := func(k interface{}) error {
+		if strings.Contains(k.(string), " ") {
+			return errors.New("contains a space")
+		}
+		return nil
+	}
+	valFn := func(k, v interface{}) error {
+		if v.(int) < 0 {
+			return errors.New("negative")
+		}
+		return nil
+	}
+	err := Entries("Counts", m, keyFn, valFn)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	expect := []string{`Counts["neg"]`, `Counts{"Bad Key"}`}
+	if fields := InvalidFields(err); !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expected %q got %q", expect, fields)
+	}
+	if msg := err.Error(); msg != `Counts{"Bad Key"}: contains a space; Counts["neg"]: negative` {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if err := Entries("Counts", m, nil, nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Entries("Counts", m, keyFn, nil); err == nil || err.Error() != `Counts{"Bad Key"}: contains a space` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestRequiredKeys(t *testing.T) {
+	regions := map[string]int{"us-west": 1, "ap-south": 2, "extra": 3}
+	err := RequiredKeys("Regions", regions, "us-east", "us-west", "eu-west")
+	if err == nil || err.Error() != `Regions["us-east"]: missing; Regions["eu-west"]: missing` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := RequiredKeys("Regions", regions, "us-west", "ap-south"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := RequiredKeys("Regions", nil, "us-east"); err == nil || err.Error() != `Regions["us-east"]: missing` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// A map remembering insertion order through its values.
+type testOrderedMap map[string]testOrderedValue
+
+type testOrderedValue struct {
+	seq   int
+	value int
+}
+
+func (m testOrderedMap) Set(k string, v int) { m[k] = testOrderedValue{len(m), v} }
+
+func (m testOrderedMap) KeyOrder() []interface{} {
+	keys := make([]interface{}, len(m))
+	for k, v := range m {
+		keys[v.seq] = k
+	}
+	return append(keys, "missing")
+}
+
+func TestEntriesKeyOrder(t *testing.T) {
+	m := testOrderedMap{}
+	m.Set("zeta", -1)
+	m.Set("alpha", -2)
+	m.Set("mid", -3)
+	err := Entries("Env", m, nil, func(k, v interface{}) error {
+		if v.(testOrderedValue).value < 0 {
+			return errors.New("negative")
+		}
+		return nil
+	})
+	expect := `Env["zeta"]: negative; Env["alpha"]: negative; Env["mid"]: negative`
+	if err == nil || err.Error() != expect {
+		t.Errorf("expected %q got %v", expect, err)
+	}
+}
+
+// A map wrapped with the order of its keys.
+type testOrderedStruct struct {
+	keys   []interface{}
+	values map[interface{}]int
+}
+
+func (m testOrderedStruct) KeyOrder() []interface{} { return m.keys }
+func (m testOrderedStruct) Map() interface{}        { return m.values }
+
+func TestEntriesOrderedMap(t *testing.T) {
+	m := testOrderedStruct{
+		keys:   []interface{}{"b", []int{1}, 3, "a"},
+		values: map[interface{}]int{"a": -1, "b": -2, 3: -3, "c": -4},
+	}
+	err := Entries("Env", m, nil, func(k, v interface{}) error {
+		if v.(int) < 0 {
+			return errors.New("negative")
+		}
+		return nil
+	})
+	expect := `Env["b"]: negative; Env[3]: negative; Env["a"]: negative; Env["c"]: negative`
+	if err == nil || err.Error() != expect {
+		t.Errorf("expected %q got %v", expect, err)
+	}
+	if err := Entries("Env", testOrderedStruct{}, nil, nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestMinItems(t *testing.T) {
+	tags := []string{"a", "b", "c"}
+	if err := MinItems("Tags", tags, 3); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := MinItems("Tags", tags, 2); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := MinItems("Tags", tags, 4)
+	if err == nil || err.Error() != "Tags: must have at least 4 items (has 3)" {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = MinItems("Labels", map[string]int{}, 1)
+	if err == nil || err.Error() != "Labels: must have at least 1 item (has 0)" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestMaxItems(t *testing.T) {
+	labels := map[string]int{"a": 1, "b": 2}
+	if err := MaxItems("Labels", labels, 2); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := MaxItems("Labels", labels, 3); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := MaxItems("Labels", labels, 1)
+	if err == nil || err.Error() != "Labels: must have at most 1 item (has 2)" {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = MaxItems("Tags", [3]int{}, 2)
+	if err == nil || err.Error() != "Tags: must have at most 2 items (has 3)" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestSameLength(t *testing.T) {
+	labels := []string{"a", "b", "c"}
+	if err := SameLength("Labels", labels, "Values", []int{1, 2, 3}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := SameLength("Labels", labels, "Values", map[string]int{"a": 1, "b": 2})
+	if err == nil || err.Error() != "Labels,Values: length mismatch (3 vs 2)" {
+		t.Errorf("unexpected error %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for non-collection")
+		}
+	}()
+	SameLength("Labels", labels, "Values", 3)
+}
+
+func TestEachPtr(t *testing.T) {
+	valid, invalid := testBar{1}, testBar{0}
+	err := EachPtr("Items", []*testBar{&valid, nil, &invalid, nil})
+	if err == nil || err.Error() != "Items[2].Baz: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = EachPtr("Items", []*testBar{&invalid, nil, &invalid})
+	if fields := InvalidFields(err); !reflect.DeepEqual(fields, []string{"Items[0].Baz", "Items[2].Baz"}) {
+		t.Errorf("unexpected fields %q", fields)
+	}
+	if err := EachPtr("Items", []*testBar{nil, &valid}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestEachFunc(t *testing.T) {
+	tags := []string{"1", "20", "x", "21"}
+	err := EachFunc("Tags", len(tags), func(i int) error {
+		if i == 3 {
+			return Skipped
+		}
+		return IntString(tags[i], 1, 20)
+	})
+	if err == nil || err.Error() != `Tags[2]: Invalid not a number: "x"` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := EachFunc("Tags", 0, nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	for _, test := range []struct {
+		err error
+		msg string
+	}{
+		{Sorted("Events", []int{1, 2, 2, 5}), ""},
+		{Sorted("Events", []int{1, 2, 2, 5, 4, 0}), "Events[4]: out of order"},
+		{Sorted("Events", []string{}), ""},
+		{SortedStrict("Events", []int{1, 2, 5}), ""},
+		{SortedStrict("Events", []int{1, 2, 2, 5}), "Events[2]: duplicate"},
+		{SortedStrict("Events", []string{"b", "a"}), "Events[1]: out of order"},
+		{SortedFunc("Events", []testQux{3, 2}, func(a, b testQux) bool { return a > b }), ""},
+		{SortedFunc("Events", []testQux{2, 3}, func(a, b testQux) bool { return a > b }), "Events[1]: out of order"},
+	} {
+		if test.msg == "" {
+			if test.err != nil {
+				t.Errorf("unexpected error %v", test.err)
+			}
+			continue
+		}
+		if test.err == nil || test.err.Error() != test.msg {
+			t.Errorf("expected %q got %v", test.msg, test.err)
+		}
+	}
+}
+
+func TestPairwise(t *testing.T) {
+	gap := func(prev, cur int) error {
+		if cur-prev > 10 {
+			return errors.New("gap too large")
+		}
+		return nil
+	}
+	err := Pairwise("Events", []int{0, 5, 10, 30, 35}, gap)
+	if err == nil || err.Error() != "Events[3]: gap too large" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Pairwise("Events", []int{0, 5, 10}, gap); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Pairwise("Events", []int{100}, gap); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestUniqueBy(t *testing.T) {
+	if err := Unique("Tags", []string{"a", "b", "A"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := UniqueBy("Tags", []string{"a", "b", "A", "B"}, strings.ToLower)
+	if err == nil || err.Error() != "Tags[2]: duplicate of [0]" {
+		t.Errorf("unexpected error %v", err)
+	}
+	type user struct {
+		ID   int
+		Name string
+	}
+	users := []user{{1, "a"}, {2, "b"}, {3, "b"}, {2, "c"}}
+	err = UniqueBy("Users", users, func(u user) int { return u.ID })
+	if err == nil || err.Error() != "Users[3]: duplicate of [1]" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestExcludes(t *testing.T) {
+	for _, test := range []struct {
+		err error
+		msg string
+	}{
+		{Excludes("Tags", []string{"a", "b"}, "admin", "root"), ""},
+		{Excludes("Tags", []string{"a", "b", "admin"}, "admin", "root"), `Tags[2]: forbidden`},
+		{Excludes("Tags", []string{"root", "a", "admin", "root"}, "admin", "root"),
+			`Tags[0]: forbidden; Tags[2]: forbidden; Tags[3]: forbidden`},
+		{Excludes("Ports", []int{22, 80}), ""},
+	} {
+		if test.msg == "" {
+			if test.err != nil {
+				t.Errorf("unexpected error %v", test.err)
+			}
+			continue
+		}
+		if test.err == nil || test.err.Error() != test.msg {
+			t.Errorf("expected %q got %v", test.msg, test.err)
+		}
+	}
+}
+
+func TestEachFrom(t *testing.T) {
+	items := make([]testBar, 120)
+	for i := range items {
+		items[i] = testBar{1}
+	}
+	items[103] = testBar{0}
+	items[115] = testBar{0}
+	err := EachFrom("Items", items[100:110], 100)
+	if err == nil || err.Error() != "Items[103].Baz: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := EachFrom("Items", items[:100], 0); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := EachFrom("Items", []testBar(nil), 10); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+type testNode struct {
+	Name     string
+	Children []*testNode
+}
+
+func (node *testNode) Validate() error {
+	var errs Errors
+	if node.Name == "" {
+		errs = append(errs, NewPropertyError("Name", ErrRequired))
+	}
+	if err := Children("Children", node.Children); err != nil {
+		errs = append(errs, err)
+	}
+	return errs.Err()
+}
+
+func TestChildren(t *testing.T) {
+	leaf := func(name string) *testNode { return &testNode{Name: name} }
+	root := &testNode{Name: "root", Children: []*testNode{
+		leaf("a"),
+		leaf("b"),
+		{Name: "c", Children: []*testNode{leaf(""), leaf("d")}},
+	}}
+	err := Property("Root", root)
+	if err == nil || err.Error() != "Root.Children[2].Children[0].Name: required" {
+		t.Errorf("unexpected error %v", err)
+	}
+	root.Children[1].Name = ""
+	err = Property("Root", root)
+	expect := []string{"Root.Children[1].Name", "Root.Children[2].Children[0].Name"}
+	if fields := InvalidFields(err); !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expected %q got %q", expect, fields)
+	}
+}
+
+func BenchmarkEachFrom10k(b *testing.B) {
+	items := make([]testBar, 10000)
+	for i := range items {
+		items[i] = testBar{testQux(i % 2)}
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := EachFrom("Items", items, 0); err == nil {
+			b.Fatal("expected an error")
+		}
+	}
+}
+
+func TestNilCollections(t *testing.T) {
+	var nilBars []testBar
+	var nilPtrs []*testBar
+	var nilMap map[string]testQux
+	for _, test := range []struct {
+		name string
+		err  error
+	}{
+		{"Entries untyped", Entries("M", nil, nil, nil)},
+		{"Entries nil", Entries("M", nilMap, nil, nil)},
+		{"Entries empty", Entries("M", map[string]testQux{}, nil, nil)},
+		{"Children nil", Children("S", nilBars)},
+		{"Children empty", Children("S", []testBar{})},
+		{"EachPtr nil", EachPtr("S", nilPtrs)},
+		{"EachFrom untyped", EachFrom("S", nil, 0)},
+		{"EachFrom nil", EachFrom("S", nilBars, 0)},
+		{"EachFrom empty", EachFrom("S", []testBar{}, 0)},
+		{"EachFunc empty", EachFunc("S", 0, nil)},
+		{"MaxItems untyped", MaxItems("S", nil, 0)},
+		{"MaxItems nil", MaxItems("S", nilBars, 0)},
+	} {
+		if test.err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, test.err)
+		}
+	}
+	for _, collection := range []interface{}{nil, nilBars, []testBar{}, nilMap} {
+		err := MinItems("S", collection, 1)
+		if err == nil || err.Error() != "S: must have at least 1 item (has 0)" {
+			t.Errorf("%#v: unexpected error %v", collection, err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"sort"
+	"sync"
+)
+
+// Accumulates the results of separate V calls, like the unrelated entities
+// of one batch request, into a single aggregate. The zero value is ready to
+// use. Unlike ConcurrentGroup and SafeCollector a Collector is not safe for
+// concurrent use.
+//
+//	var c validate.Collector
+//	c.AddNamed("user", user)
+//	c.AddNamed("order", order)
+//	err := c.Err()
+type Collector struct {
+	errs    Errors
+	results map[string]error
+}
+
+// Validate v (see V) and keep any failure.
+func (c *Collector) Add(v interface{}) {
+	if err := V(v); err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+// Validate v as the property name (see Property) and keep any failure.
+func (c *Collector) AddNamed(name string, v interface{}) {
+	if err := Property(name, v); err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+// Validate v, an object of a batch identified by the client-supplied id, and
+// record its result for Results. A failure is also kept for Err, attributed
+// to id as with AddNamed. IDs should be unique; adding an id again replaces
+// its result in Results, while Err keeps every failure.
+func (c *Collector) AddWithID(id string, v interface{}) {
+	if c.results == nil {
+		c.results = make(map[string]error)
+	}
+	err := V(v)
+	c.results[id] = err
+	if err != nil {
+		c.errs = append(c.errs, PropertyError{property: id, err: err})
+	}
+}
+
+// The result of every object added with AddWithID, keyed by its id. Valid
+// objects map to nil, and failure paths are relative to the object.
+func (c *Collector) Results() map[string]error {
+	results := make(map[string]error, len(c.results))
+	for id, err := range c.results {
+		results[id] = err
+	}
+	return results
+}
+
+// Make room for n more failures, so that collecting them does not grow the
+// aggregate again and again, as for a large batch expected to be mostly
+// invalid. Grow is only a hint; collecting more than n failures still works.
+//
+//	c.Grow(len(batch))
+func (c *Collector) Grow(n int) {
+	if n > 0 && cap(c.errs)-len(c.errs) < n {
+		c.errs = append(make(Errors, 0, len(c.errs)+n), c.errs...)
+	}
+}
+
+// The failures collected so far in the order they were added, or nil.
+func (c *Collector) Err() error {
+	return c.errs.Err()
+}
+
+// Accumulates errors from goroutines started by the caller, for concurrent
+// validation that does not fit a ConcurrentGroup. Add is safe for
+// concurrent use; call Err once every goroutine has finished adding.
+//
+//	c := validate.NewSafeCollector()
+//	for _, item := range items {
+//		wg.Add(1)
+//		go func() { defer wg.Done(); c.Add(validate.V(item)) }()
+//	}
+//	wg.Wait()
+//	err := c.Err()
+type SafeCollector struct {
+	mu   sync.Mutex
+	errs Errors
+}
+
+// A SafeCollector with no errors.
+func NewSafeCollector() *SafeCollector {
+	return &SafeCollector{}
+}
+
+// Keep err if it is not nil.
+func (c *SafeCollector) Add(err error) {
+	if err == nil {
+		return
+	}
+	c.mu.Lock()
+	c.errs = append(c.errs, err)
+	c.mu.Unlock()
+}
+
+// The leaves of every error added, sorted by path and then by message so
+// that the result does not depend on the order goroutines finished in, or
+// nil if none failed.
+func (c *SafeCollector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	flat := c.errs.Flatten()
+	sort.SliceStable(flat, func(i, j int) bool {
+		if pi, pj := leafPath(flat[i]), leafPath(flat[j]); pi != pj {
+			return pi < pj
+		}
+		return flat[i].Error() < flat[j].Error()
+	})
+	return flat.Err()
+}
+
+// The full path of a flattened leaf, or "" if it has none.
+func leafPath(leaf error) string {
+	if e, ok := leaf.(PropertyError); ok {
+		return e.path(false)
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	var c Collector
+	if err := c.Err(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	c.AddNamed("first", testBar{0})
+	c.AddNamed("second", testBar{1})
+	c.AddNamed("third", testQux(0))
+	c.Add(testQux(0))
+	err := c.Err()
+	if err == nil || err.Error() != "first.Baz: qux; third: qux; qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestCollectorResults(t *testing.T) {
+	var c Collector
+	c.AddWithID("a1", testBar{0})
+	c.AddWithID("b2", testBar{1})
+	c.AddWithID("c3", testQux(0))
+	results := c.Results()
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results got %v", results)
+	}
+	if err := results["a1"]; err == nil || err.Error() != "Baz: qux" {
+		t.Errorf("a1: unexpected result %v", err)
+	}
+	if err, ok := results["b2"]; !ok || err != nil {
+		t.Errorf("b2: unexpected result %v", err)
+	}
+	if err := results["c3"]; err == nil || err.Error() != "qux" {
+		t.Errorf("c3: unexpected result %v", err)
+	}
+	if err := c.Err(); err == nil || err.Error() != "a1.Baz: qux; c3: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+	c.AddWithID("a1", testBar{1})
+	if err := c.Results()["a1"]; err != nil {
+		t.Errorf("a1: expected replaced result got %v", err)
+	}
+}
+
+func TestCollectorGrow(t *testing.T) {
+	var c Collector
+	c.Add(testQux(0))
+	c.Grow(10)
+	if cap(c.errs) < 11 {
+		t.Errorf("expected room for 11 failures got %d", cap(c.errs))
+	}
+	for i := 0; i < 20; i++ {
+		c.Add(testQux(0))
+	}
+	if n := len(c.Err().(Errors)); n != 21 {
+		t.Errorf("expected 21 failures got %d", n)
+	}
+}
+
+func benchmarkCollector10k(b *testing.B, grow bool) {
+	items := make([]testQux, 10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var c Collector
+		if grow {
+			c.Grow(len(items))
+		}
+		for _, item := range items {
+			c.Add(item)
+		}
+		if c.Err() == nil {
+			b.Fatal("expected an error")
+		}
+	}
+}
+
+func BenchmarkCollector10k(b *testing.B)     { benchmarkCollector10k(b, false) }
+func BenchmarkCollector10kGrow(b *testing.B) { benchmarkCollector10k(b, true) }
+
+func TestSafeCollector(t *testing.T) {
+	c := NewSafeCollector()
+	if err := c.Err(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Add(Index(i, testBar{testQux(i % 10)}))
+		}()
+	}
+	wg.Wait()
+	c.Add(errors.New("plain"))
+	err := c.Err()
+	if n := len(flatten(err)); n != 6 {
+		t.Fatalf("expected 6 leaves got %d: %v", n, err)
+	}
+	expect := "plain; [0].Baz: qux; [10].Baz: qux; [20].Baz: qux; [30].Baz: qux; [40].Baz: qux"
+	if err.Error() != expect {
+		t.Errorf("expected %q got %q", expect, err)
+	}
+}
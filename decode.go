@@ -0,0 +1,333 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Decode r into v using the named format, "json" or "xml", then validate
+// v. A decoding error is returned as is, before any validation happens.
+//
+//	err := validate.Decode("json", req.Body, cfg)
+func Decode(format string, r io.Reader, v interface{}) error {
+	var err error
+	switch format {
+	case "json":
+		err = json.NewDecoder(r).Decode(v)
+	case "xml":
+		err = xml.NewDecoder(r).Decode(v)
+	default:
+		return fmt.Errorf("validate: unknown format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	return V(v)
+}
+
+// Decode a JSON array from r one element at a time, validating each (see V)
+// as soon as it is decoded, so memory use is bounded by a single element.
+// newElem returns a pointer to decode the next element into. onError is
+// called with the index and failure of each invalid element. A decoding
+// error, including input that is not an array, stops decoding and is
+// returned.
+//
+//	err := validate.DecodeJSONArray(r, func() interface{} { return new(Item) },
+//		func(i int, err error) { log.Printf("item %d: %v", i, err) })
+func DecodeJSONArray(r io.Reader, newElem func() interface{}, onError func(index int, err error)) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("validate: expected a JSON array, found %v", tok)
+	}
+	for i := 0; dec.More(); i++ {
+		elem := newElem()
+		if err := dec.Decode(elem); err != nil {
+			return err
+		}
+		if err := V(elem); err != nil {
+			onError(i, err)
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// The error reported by UnmarshalStrict for keys that do not match a field.
+var ErrUnknownField = errors.New("unknown field")
+
+// Unmarshal JSON data into v, rejecting object keys that do not match a
+// field of v, then validate v. Keys are matched to fields by their JSON
+// names as encoding/json matches them, case-insensitively and including
+// the fields of embedded structs. Every unknown key is reported with
+// ErrUnknownField at its path in data, such as a key in a nested object, an
+// array element or a map value. Values decoded by a json.Unmarshaler, and
+// interfaces, accept any keys. Data is decoded with DisallowUnknownFields,
+// so an unknown key the paths miss is still rejected, as a plain error.
+//
+//	UnmarshalStrict([]byte(`{"nmae": "x"}`), cfg) // `nmae: unknown field`
+func UnmarshalStrict(data []byte, v interface{}) error {
+	var raw json.RawMessage
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&raw); err == nil {
+		if err := unknownFields(raw, reflect.TypeOf(v)); err != nil {
+			return UnwrapSingle(err)
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	return V(v)
+}
+
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// The keys of the JSON value data that no field of typ decodes, with their
+// paths. Values that do not have the shape typ expects are left for the
+// decoder to report.
+func unknownFields(data json.RawMessage, typ reflect.Type) error {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || reflect.PointerTo(typ).Implements(jsonUnmarshaler) {
+		return nil
+	}
+	var errs Errors
+	switch typ.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return nil
+		}
+		fields := jsonFields(typ)
+		for _, key := range sortedJSONKeys(obj) {
+			field, ok := matchJSONField(fields, key)
+			if !ok {
+				errs = append(errs, PropertyError{property: key, err: ErrUnknownField})
+			} else if err := unknownFields(obj[key], field.typ); err != nil {
+				errs = append(errs, PropertyError{property: key, err: err})
+			}
+		}
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return nil
+		}
+		for _, key := range sortedJSONKeys(obj) {
+			if err := unknownFields(obj[key], typ.Elem()); err != nil {
+				errs = append(errs, PropertyError{index: key, err: err})
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var elems []json.RawMessage
+		if json.Unmarshal(data, &elems) != nil {
+			return nil
+		}
+		for i, elem := range elems {
+			if err := unknownFields(elem, typ.Elem()); err != nil {
+				errs = append(errs, PropertyError{index: i, err: err})
+			}
+		}
+	}
+	return errs.Err()
+}
+
+func sortedJSONKeys(obj map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// A field of a struct as encoding/json sees it.
+type jsonField struct {
+	name   string
+	index  []int
+	typ    reflect.Type
+	tagged bool
+}
+
+// The field decoding key: the field named key, or else the first field, in
+// field order, whose name matches key case-insensitively.
+func matchJSONField(fields []jsonField, key string) (jsonField, bool) {
+	for _, field := range fields {
+		if field.name == key {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.name, key) {
+			return field, true
+		}
+	}
+	return jsonField{}, false
+}
+
+// The fields of the struct type typ in field order, following the rules of
+// encoding/json: a valid json tag names a field and "-" hides it, and the
+// fields of untagged embedded structs are promoted. Of fields sharing a
+// name, the least deeply embedded wins, then the only tagged one at that
+// depth; if neither decides, the name is dropped.
+func jsonFields(typ reflect.Type) []jsonField {
+	var fields []jsonField
+	decided := make(map[string]bool)
+	level := []jsonField{{typ: typ}}
+	count := map[reflect.Type]int{typ: 1}
+	seen := map[reflect.Type]bool{typ: true}
+	for len(level) > 0 {
+		var next []jsonField
+		nextCount := make(map[reflect.Type]int)
+		found := make(map[string][]jsonField)
+		var names []string
+		for _, parent := range level {
+			t := parent.typ
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				ftype := field.Type
+				if ftype.Name() == "" && ftype.Kind() == reflect.Ptr {
+					ftype = ftype.Elem()
+				}
+				if field.Anonymous {
+					if field.PkgPath != "" && ftype.Kind() != reflect.Struct {
+						continue
+					}
+				} else if field.PkgPath != "" {
+					continue
+				}
+				tag := field.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+				if !validJSONName(name) {
+					name = ""
+				}
+				index := append(parent.index[:len(parent.index):len(parent.index)], i)
+				if name == "" && field.Anonymous && ftype.Kind() == reflect.Struct {
+					if nextCount[ftype]++; nextCount[ftype] == 1 && !seen[ftype] {
+						seen[ftype] = true
+						next = append(next, jsonField{index: index, typ: ftype})
+					}
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = field.Name
+				}
+				if decided[name] {
+					continue
+				}
+				if _, ok := found[name]; !ok {
+					names = append(names, name)
+				}
+				f := jsonField{name, index, ftype, tagged}
+				found[name] = append(found[name], f)
+				if count[t] > 1 {
+					// A struct embedded twice at one depth
+					// conflicts with itself.
+					found[name] = append(found[name], f)
+				}
+			}
+		}
+		for _, name := range names {
+			decided[name] = true
+			if field, ok := dominantJSONField(found[name]); ok {
+				fields = append(fields, field)
+			}
+		}
+		level, count = next, nextCount
+	}
+	sort.Slice(fields, func(i, j int) bool { return lessIndex(fields[i].index, fields[j].index) })
+	return fields
+}
+
+// Whether encoding/json accepts name from a json tag as a field name.
+func validJSONName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c) && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// The one field of a name at a depth that encoding/json decodes, if any.
+func dominantJSONField(fields []jsonField) (jsonField, bool) {
+	if len(fields) == 1 {
+		return fields[0], true
+	}
+	var dominant []jsonField
+	for _, field := range fields {
+		if field.tagged {
+			dominant = append(dominant, field)
+		}
+	}
+	if len(dominant) == 1 {
+		return dominant[0], true
+	}
+	return jsonField{}, false
+}
+
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// The error reported by JSONField for a string that is not valid JSON.
+var ErrInvalidJSON = errors.New("invalid JSON")
+
+// Parse s, a string field holding JSON, into into and validate the result
+// (see V). Used inside PropertyFunc, content failures are attributed to
+// paths below the field and a parse failure to the field itself.
+//
+//	validator.PropertyFunc("Metadata", func() error {
+//		return validator.JSONField(rec.Metadata, &Metadata{})
+//	}) // `Metadata.k: required` or `Metadata: invalid JSON`
+func JSONField(s string, into interface{}) error {
+	err := json.Unmarshal([]byte(s), into)
+	switch err.(type) {
+	case nil:
+		return V(into)
+	case *json.InvalidUnmarshalError:
+		return err
+	}
+	return ErrInvalidJSON
+}
+
+// The keys of the JSON object data, for use with RequirePresent.
+func PresentFields(data []byte) (map[string]bool, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool, len(fields))
+	for name := range fields {
+		present[name] = true
+	}
+	return present, nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testConfig struct {
+	Name string `json:"name" xml:"name"`
+	Port int    `json:"port" xml:"port"`
+}
+
+func (cfg *testConfig) Validate() error {
+	return PropertyFunc("Port", func() error {
+		if cfg.Port <= 0 {
+			return Invalid(cfg.Port)
+		}
+		return nil
+	})
+}
+
+func TestDecode(t *testing.T) {
+	for _, test := range []struct {
+		format, input, msg string
+	}{
+		{"json", `{"name": "a", "port": 80}`, ""},
+		{"json", `{"name": "a", "port": 0}`, "Port: Invalid: 0"},
+		{"json", `{"name": `, "unexpected EOF"},
+		{"xml", `<config><name>a</name><port>80</port></config>`, ""},
+		{"xml", `<config><name>a</name><port>-1</port></config>`, "Port: Invalid: -1"},
+		{"xml", `<config><name>a</name>`, "XML syntax error on line 1: unexpected EOF"},
+		{"yaml", `name: a`, `validate: unknown format "yaml"`},
+	} {
+		err := Decode(test.format, strings.NewReader(test.input), new(testConfig))
+		switch {
+		case test.msg == "" && err != nil:
+			t.Errorf("%s %q: unexpected error %v", test.format, test.input, err)
+		case test.msg != "" && (err == nil || err.Error() != test.msg):
+			t.Errorf("%s %q: expected %q got %v", test.format, test.input, test.msg, err)
+		}
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	for _, test := range []struct {
+		input, msg string
+	}{
+		{`{"name": "a", "port": 80}`, ""},
+		{`{"name": "a", "port": 80, "hots": "x"}`, "hots: unknown field"},
+		{`{"name": "a", "port": 0}`, "Port: Invalid: 0"},
+	} {
+		err := UnmarshalStrict([]byte(test.input), new(testConfig))
+		switch {
+		case test.msg == "" && err != nil:
+			t.Errorf("%q: unexpected error %v", test.input, err)
+		case test.msg != "" && (err == nil || err.Error() != test.msg):
+			t.Errorf("%q: expected %q got %v", test.input, test.msg, err)
+		}
+	}
+	if err := UnmarshalStrict([]byte(`{"port": "80"}`), new(testConfig)); err == nil || IsValidationError(err) {
+		t.Errorf("unexpected error for mistyped field %v", err)
+	}
+	err := UnmarshalStrict([]byte(`{"hots": "x"}`), new(testConfig))
+	if perr, ok := err.(PropertyError); !ok || perr.OriginatingError() != ErrUnknownField {
+		t.Errorf("unexpected error %#v", err)
+	}
+}
+
+type testServer struct {
+	testConfig
+	Hosts   []testConfig      `json:"hosts"`
+	Primary *testConfig       `json:"primary"`
+	Labels  map[string]string `json:"labels"`
+	Secret  string            `json:"-"`
+}
+
+func TestUnmarshalStrictNested(t *testing.T) {
+	for _, test := range []struct {
+		input, msg string
+	}{
+		{`{"NAME": "a", "port": 80, "hosts": [{"port": 1}], "primary": {"port": 2}, "labels": {"x": "y"}}`, ""},
+		{`{"port": 80, "primary": {"port": 2, "hots": "x"}}`, "primary.hots: unknown field"},
+		{`{"port": 80, "hosts": [{"port": 1}, {"prot": 1}], "Secret": "s", "testConfig": {}}`,
+			"Secret: unknown field; hosts[1].prot: unknown field; testConfig: unknown field"},
+	} {
+		err := UnmarshalStrict([]byte(test.input), new(testServer))
+		switch {
+		case test.msg == "" && err != nil:
+			t.Errorf("%q: unexpected error %v", test.input, err)
+		case test.msg != "" && (err == nil || err.Error() != test.msg):
+			t.Errorf("%q: expected %q got %v", test.input, test.msg, err)
+		}
+	}
+	err := UnmarshalStrict([]byte(`{"port": 80, "primary": {"hots": "x"}}`), new(testServer))
+	if group := ByRootField(err)["primary"]; group == nil || group.Error() != "hots: unknown field" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+type testCatalog struct {
+	Items map[string]testConfig `json:"items"`
+	testLeft
+	testRight
+	First  testConfig     `json:"Key"`
+	Second map[string]int `json:"KEY"`
+}
+
+type testLeft struct{ ID, Left int }
+type testRight struct{ ID, Right int }
+
+func TestUnmarshalStrictCatalog(t *testing.T) {
+	for _, test := range []struct {
+		input, msg string
+	}{
+		{`{"items": {"a": {"port": 1}}, "Left": 1, "right": 2, "key": {"port": 1}}`, ""},
+		{`{"items": {"a": {"port": 1}, "b": {"name": "x", "nmae": "y", "port": 1}}}`, `items["b"].nmae: unknown field`},
+		{`{"ID": 1}`, "ID: unknown field"},
+		{`{"key": {"port": 1, "hots": "x"}}`, "key.hots: unknown field"},
+	} {
+		err := UnmarshalStrict([]byte(test.input), new(testCatalog))
+		switch {
+		case test.msg == "" && err != nil:
+			t.Errorf("%q: unexpected error %v", test.input, err)
+		case test.msg != "" && (err == nil || err.Error() != test.msg):
+			t.Errorf("%q: expected %q got %v", test.input, test.msg, err)
+		}
+	}
+}
+
+func TestJSONField(t *testing.T) {
+	for _, test := range []struct {
+		s   string
+		msg string
+	}{
+		{`{"port": 80}`, ""},
+		{`{"port": 0}`, "Metadata.Port: Invalid: 0"},
+		{`{"port": `, "Metadata: invalid JSON"},
+	} {
+		err := PropertyFunc("Metadata", func() error {
+			return JSONField(test.s, &testConfig{})
+		})
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", test.s, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%q: expected %q got %v", test.s, test.msg, err)
+		}
+	}
+	if err := JSONField(`{}`, testConfig{}); err == ErrInvalidJSON {
+		t.Errorf("non-pointer reported as invalid JSON")
+	}
+}
+
+func TestDecodeJSONArray(t *testing.T) {
+	r := strings.NewReader(`[{"port": 80}, {"port": 0}, {"port": 443}, {"port": -1}]`)
+	var failures []string
+	err := DecodeJSONArray(r, func() interface{} { return new(testConfig) }, func(i int, err error) {
+		failures = append(failures, fmt.Sprintf("%d: %v", i, err))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"1: Port: Invalid: 0", "3: Port: Invalid: -1"}
+	if !reflect.DeepEqual(failures, expect) {
+		t.Errorf("expected %q got %q", expect, failures)
+	}
+	for _, input := range []string{`{"port": 80}`, `[{"port": 80},`, `[{"port": "x"}]`} {
+		err := DecodeJSONArray(strings.NewReader(input), func() interface{} { return new(testConfig) }, func(int, error) {})
+		if err == nil {
+			t.Errorf("%s: expected a decoding error", input)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A path mapper for MapPaths and Render naming the environment variable a
+// path came from, for configuration read from the environment. The path is
+// split into words, which are upper cased and joined by "_":
+//
+//	properties split at case changes  DB.Pool.MaxConns    DB_POOL_MAX_CONNS
+//	indices become a numeric suffix   Servers[2]          SERVERS_2
+//	quoted map keys are unquoted      Regions["us-east"]  REGIONS_US_EAST
+//
+// Any character other than a letter or digit separates words.
+//
+//	MapPaths(err, EnvStyle) // `DB_POOL_MAX_CONNS: must be positive`
+func EnvStyle(path string) string {
+	var words []string
+	for len(path) > 0 {
+		if path[0] == '"' {
+			if quoted, err := strconv.QuotedPrefix(path); err == nil {
+				key, _ := strconv.Unquote(quoted)
+				words = append(words, envWords(key, false)...)
+				path = path[len(quoted):]
+				continue
+			}
+		}
+		end := strings.IndexByte(path[1:], '"') + 1
+		if end == 0 {
+			end = len(path)
+		}
+		words = append(words, envWords(path[:end], true)...)
+		path = path[end:]
+	}
+	return strings.Join(words, "_")
+}
+
+// The upper cased words of s, split at characters other than letters and
+// digits and, with camel, where a word in camel case starts.
+func envWords(s string, camel bool) []string {
+	var words []string
+	var word []rune
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+		if camel && len(word) > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, unicode.ToUpper(r))
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
@@ -0,0 +1,37 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEnvStyle(t *testing.T) {
+	for _, test := range []struct{ path, env string }{
+		{"DB.Pool.MaxConns", "DB_POOL_MAX_CONNS"},
+		{"Servers[2]", "SERVERS_2"},
+		{"HTTPServer.Port", "HTTP_SERVER_PORT"},
+		{`Regions["us-east"].Zone`, "REGIONS_US_EAST_ZONE"},
+		{`Labels{"a.b"}`, "LABELS_A_B"},
+		{"Listen2.Addr", "LISTEN2_ADDR"},
+		{"", ""},
+	} {
+		if env := EnvStyle(test.path); env != test.env {
+			t.Errorf("%q: expected %q got %q", test.path, test.env, env)
+		}
+	}
+}
+
+func TestEnvStyleMapPaths(t *testing.T) {
+	err := Errors{
+		Nest("DB", Nest("Pool", NewPropertyError("Max", errors.New("must be positive")))),
+		elementError("Servers", 2, errors.New("bad address")),
+	}
+	mapped := MapPaths(err, EnvStyle)
+	if mapped == nil || mapped.Error() != "DB_POOL_MAX: must be positive; SERVERS_2: bad address" {
+		t.Errorf("unexpected error %v", mapped)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Validates properties concurrently with a bounded number of goroutines,
+// like errgroup.Group, collecting every failure with its property path.
+//
+//	g := validate.NewConcurrentGroup(ctx, 4)
+//	g.Go("Avatar", func() error { return checkURL(ctx, user.Avatar) })
+//	g.Go("Email", func() error { return checkMX(ctx, user.Email) })
+//	err := g.Wait()
+type ConcurrentGroup struct {
+	ctx  context.Context
+	sem  chan struct{}
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	next int
+	errs []groupFailure
+}
+
+// A failure of the validation added to a group n-th.
+type groupFailure struct {
+	n   int
+	err PropertyError
+}
+
+// A group running at most limit validations at once. A limit less than 1
+// means no limit. Validations that have not started when ctx is done are
+// skipped and reported as failing with ctx.Err(). Failures with ctx.Err(),
+// including those returned by a validation once ctx is done, are not
+// validation errors (see IsValidationError).
+func NewConcurrentGroup(ctx context.Context, limit int) *ConcurrentGroup {
+	g := &ConcurrentGroup{ctx: ctx}
+	if limit > 0 {
+		g.sem = make(chan struct{}, limit)
+	}
+	return g
+}
+
+// Validate property with fn in a new goroutine (see PropertyFunc). Go blocks
+// while the group is at its limit.
+func (g *ConcurrentGroup) Go(property string, fn func() error) {
+	g.mu.Lock()
+	n := g.next
+	g.next++
+	g.mu.Unlock()
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		case <-g.ctx.Done():
+			g.add(n, NewPropertyError(property, internalError{g.ctx.Err()}))
+			return
+		}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		if err := g.ctx.Err(); err != nil {
+			g.add(n, NewPropertyError(property, internalError{err}))
+			return
+		}
+		g.add(n, PropertyFunc(property, func() error {
+			err := fn()
+			if ctxErr := g.ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+				return internalError{err}
+			}
+			return err
+		}))
+	}()
+}
+
+func (g *ConcurrentGroup) add(n int, err error) {
+	if err == nil {
+		return
+	}
+	g.mu.Lock()
+	g.errs = append(g.errs, groupFailure{n, err.(PropertyError)})
+	g.mu.Unlock()
+}
+
+// Wait for all validations to finish. The failures are returned as Errors
+// sorted by property, and failures of one property in the order their
+// validations were added with Go, however long each took.
+func (g *ConcurrentGroup) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	sort.Slice(g.errs, func(i, j int) bool {
+		a, b := g.errs[i], g.errs[j]
+		if pa, pb := a.err.Property(), b.err.Property(); pa != pb {
+			return pa < pb
+		}
+		return a.n < b.n
+	})
+	var errs Errors
+	for _, f := range g.errs {
+		errs = append(errs, f.err)
+	}
+	return errs.Err()
+}
+
+// Clear the failures collected by g so it can be reused for another round
+// of validations, keeping its context and limit. Reset must only be called
+// after Wait has returned, never concurrently with Go or Wait. Errors
+// previously returned by Wait are not affected.
+func (g *ConcurrentGroup) Reset() {
+	g.mu.Lock()
+	g.errs, g.next = nil, 0
+	g.mu.Unlock()
+}
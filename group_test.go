@@ -0,0 +1,138 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrentGroup(t *testing.T) {
+	var running, peak int32
+	check := func(err error) func() error {
+		return func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return err
+		}
+	}
+	g := NewConcurrentGroup(context.Background(), 2)
+	g.Go("Zip", check(errors.New("unknown")))
+	g.Go("Name", check(nil))
+	g.Go("Avatar", check(Property("URL", testQux(0))))
+	g.Go("Email", check(nil))
+	err := g.Wait()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	expect := []string{"Avatar.URL", "Zip"}
+	if fields := InvalidFields(err); !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expected %q got %q", expect, fields)
+	}
+	if msg := err.Error(); msg != "Avatar.URL: qux; Zip: unknown" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if peak > 2 {
+		t.Errorf("%d validations ran at once", peak)
+	}
+}
+
+func TestConcurrentGroupOrder(t *testing.T) {
+	g := NewConcurrentGroup(context.Background(), 0)
+	for i, delay := range []time.Duration{20, 10, 0} {
+		msg := fmt.Sprint("check ", i)
+		delay := delay * time.Millisecond
+		g.Go("Email", func() error { time.Sleep(delay); return errors.New(msg) })
+	}
+	g.Go("Avatar", func() error { return errors.New("check 3") })
+	err := g.Wait()
+	if expect := "Avatar: check 3; Email: check 0; Email: check 1; Email: check 2"; err == nil || err.Error() != expect {
+		t.Errorf("expected %q got %v", expect, err)
+	}
+}
+
+func TestConcurrentGroupCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	g := NewConcurrentGroup(ctx, 1)
+	called := false
+	g.Go("Name", func() error { called = true; return nil })
+	err := g.Wait()
+	if called {
+		t.Errorf("validation ran after cancellation")
+	}
+	errs, ok := err.(Errors)
+	if !ok || len(errs) != 1 || errs[0].(PropertyError).OriginatingError() != context.Canceled {
+		t.Errorf("unexpected error %v", err)
+	}
+	if IsValidationError(err) {
+		t.Errorf("cancellation reported as a validation error")
+	}
+}
+
+func TestConcurrentGroupCanceledDuring(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := NewConcurrentGroup(ctx, 0)
+	g.Go("A", func() error { cancel(); return ctx.Err() })
+	err := g.Wait()
+	if err == nil || err.Error() != "A: context canceled" || IsValidationError(err) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestConcurrentGroupReset(t *testing.T) {
+	g := NewConcurrentGroup(context.Background(), 2)
+	g.Go("Name", func() error { return errors.New("required") })
+	first := g.Wait()
+	if first == nil {
+		t.Fatal("expected an error")
+	}
+	g.Reset()
+	if err := g.Wait(); err != nil {
+		t.Errorf("unexpected error after reset %v", err)
+	}
+	g.Go("Email", func() error { return errors.New("required") })
+	if err := g.Wait(); err == nil || err.Error() != "Email: required" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if first.Error() != "Name: required" {
+		t.Errorf("reset changed a previous result %v", first)
+	}
+}
+
+func benchmarkGroupRound(g *ConcurrentGroup) {
+	for _, prop := range []string{"A", "B", "C", "D"} {
+		g.Go(prop, func() error { return nil })
+	}
+	g.Wait()
+}
+
+func BenchmarkConcurrentGroupFresh(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkGroupRound(NewConcurrentGroup(context.Background(), 2))
+	}
+}
+
+func BenchmarkConcurrentGroupReset(b *testing.B) {
+	b.ReportAllocs()
+	g := NewConcurrentGroup(context.Background(), 2)
+	for i := 0; i < b.N; i++ {
+		g.Reset()
+		benchmarkGroupRound(g)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+)
+
+// Like Property, but every failure carries hint, a remediation hint for
+// users. The hint does not change Error(); it is available from the Hint()
+// method of the resulting PropertyError, however deeply nested, and is
+// included in MarshalAPIErrors output.
+//
+//	Hinted("Password", Password(pw), "use at least 12 characters") // `Password: too short`
+func Hinted(property string, value interface{}, hint string) error {
+	return PropertyFunc(property, func() error {
+		return mapLeaves(V(value), func(path string, leaf error) error {
+			return hintedError{leaf, hint}
+		})
+	})
+}
+
+// A leaf error carrying a remediation hint.
+type hintedError struct {
+	err  error
+	hint string
+}
+
+func (err hintedError) Error() string { return err.err.Error() }
+func (err hintedError) Unwrap() error { return err.err }
+func (err hintedError) Code() string  { return codeOf(err.err) }
+
+// The remediation hint of the error originating err, if any (see Hinted).
+func (err PropertyError) Hint() string {
+	return hintOf(err.leaf())
+}
+
+func hintOf(leaf error) string {
+	var h hintedError
+	if errors.As(leaf, &h) {
+		return h.hint
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+)
+
+func TestHinted(t *testing.T) {
+	err := Hinted("Bar", testBar{0}, "use a non-zero Baz")
+	if err == nil || err.Error() != "Bar.Baz: qux" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	nested := Nest("Foo", err.(PropertyError))
+	if hint := nested.Hint(); hint != "use a non-zero Baz" {
+		t.Errorf("unexpected hint %q", hint)
+	}
+	if hint := NewPropertyError("Bar", ErrRequired).Hint(); hint != "" {
+		t.Errorf("unexpected hint %q", hint)
+	}
+	p, e := MarshalAPIErrors(Errors{nested, NewPropertyError("Email", ErrRequired)})
+	if e != nil {
+		t.Fatal(e)
+	}
+	expect := `{"errors":[` +
+		`{"field":"Foo.Bar.Baz","code":"invalid","message":"qux","hint":"use a non-zero Baz"},` +
+		`{"field":"Email","code":"invalid","message":"required"}]}`
+	if string(p) != expect {
+		t.Errorf("expected %s got %s", expect, p)
+	}
+	if err := Hinted("Bar", testBar{1}, "unused"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
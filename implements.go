@@ -0,0 +1,29 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Require that the dynamic type of value implements an interface, as for a
+// plugin that must satisfy a contract. The interface is given as a nil
+// pointer to it, since an interface type has no values of its own; anything
+// other than a pointer to an interface type panics. A nil value implements
+// no interface.
+//
+//	Implements(plugin, (*io.Closer)(nil)) // `Invalid: does not implement io.Closer`
+func Implements(value interface{}, ifacePtr interface{}) error {
+	typ := reflect.TypeOf(ifacePtr)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("validate: Implements of non-interface-pointer type %T", ifacePtr))
+	}
+	iface := typ.Elem()
+	if value == nil || !reflect.TypeOf(value).Implements(iface) {
+		return InvalidError{reason: "does not implement " + iface.String()}
+	}
+	return nil
+}
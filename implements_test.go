@@ -0,0 +1,29 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestImplements(t *testing.T) {
+	if err := Implements(testBar{}, (*Interface)(nil)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := Implements(3, (*fmt.Stringer)(nil))
+	if err == nil || err.Error() != "Invalid: does not implement fmt.Stringer" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Implements(nil, (*Interface)(nil)); err == nil {
+		t.Errorf("expected an error for nil")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for non-interface pointer")
+		}
+	}()
+	Implements(testBar{}, Interface(nil))
+}
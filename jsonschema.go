@@ -0,0 +1,205 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Check instance, marshaled to JSON, against a JSON Schema document. Only a
+// pragmatic subset of keywords is supported:
+//
+//	type        a type name or list of names, "integer" included
+//	required    property names an object must have
+//	minimum     an inclusive lower bound for numbers
+//	pattern     a regular expression (RE2 syntax) strings must match
+//	properties  schemas for the properties of an object
+//	items       a schema for every element of an array
+//
+// Other keywords are ignored. Failures are nested PropertyErrors, one per
+// object key and array element on the way to the offending value, as V
+// reports them, so PointerErrors keys them by JSON Pointer, like
+// "/address/zip"; failures of the instance itself have no path. An unusable
+// schema or an instance that cannot be marshaled is reported as a plain
+// error.
+//
+//	ValidateJSONSchema(schema, user) // `age: must be at least 18`
+func ValidateJSONSchema(schema []byte, instance interface{}) error {
+	var s map[string]interface{}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return fmt.Errorf("validate: invalid JSON schema: %v", err)
+	}
+	data, err := json.Marshal(instance)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var errs Errors
+	if err := checkJSONSchema(v, s, nil, &errs); err != nil {
+		return err
+	}
+	return errs.Err()
+}
+
+// Check v at path against the schema s, adding failures to errs. The path
+// holds the object keys (strings) and array indexes (ints) leading to v. An
+// error is returned only for an unusable schema.
+func checkJSONSchema(v interface{}, s map[string]interface{}, path []interface{}, errs *Errors) error {
+	fail := func(path []interface{}, err error) {
+		*errs = append(*errs, jsonPathError(path, err))
+	}
+	if t, ok := s["type"]; ok {
+		types, err := schemaTypes(t)
+		if err != nil {
+			return err
+		}
+		if !hasJSONType(v, types) {
+			fail(path, fmt.Errorf("must be of type %s (is %s)", strings.Join(types, " or "), jsonType(v)))
+			return nil
+		}
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if req, ok := s["required"].([]interface{}); ok {
+			for _, name := range req {
+				if name, ok := name.(string); ok {
+					if _, present := v[name]; !present {
+						fail(jsonPath(path, name), ErrRequired)
+					}
+				}
+			}
+		}
+		props, _ := s["properties"].(map[string]interface{})
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			sub, ok := props[name].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("validate: invalid JSON schema for property %q", name)
+			}
+			if elem, present := v[name]; present {
+				if err := checkJSONSchema(elem, sub, jsonPath(path, name), errs); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		if items, ok := s["items"]; ok {
+			sub, ok := items.(map[string]interface{})
+			if !ok {
+				return errors.New("validate: invalid JSON schema for items")
+			}
+			for i, elem := range v {
+				if err := checkJSONSchema(elem, sub, jsonPath(path, i), errs); err != nil {
+					return err
+				}
+			}
+		}
+	case float64:
+		if min, ok := s["minimum"]; ok {
+			min, ok := min.(float64)
+			if !ok {
+				return errors.New("validate: invalid JSON schema minimum")
+			}
+			if v < min {
+				fail(path, fmt.Errorf("must be at least %v", min))
+			}
+		}
+	case string:
+		if p, ok := s["pattern"]; ok {
+			p, ok := p.(string)
+			if !ok {
+				return errors.New("validate: invalid JSON schema pattern")
+			}
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("validate: invalid JSON schema pattern: %v", err)
+			}
+			if !re.MatchString(v) {
+				fail(path, Invalid("does not match "+p, v))
+			}
+		}
+	}
+	return nil
+}
+
+// The type names of a schema's type keyword.
+func schemaTypes(t interface{}) ([]string, error) {
+	switch t := t.(type) {
+	case string:
+		return []string{t}, nil
+	case []interface{}:
+		types := make([]string, len(t))
+		for i, name := range t {
+			name, ok := name.(string)
+			if !ok {
+				return nil, errors.New("validate: invalid JSON schema type")
+			}
+			types[i] = name
+		}
+		return types, nil
+	}
+	return nil, errors.New("validate: invalid JSON schema type")
+}
+
+func hasJSONType(v interface{}, types []string) bool {
+	actual := jsonType(v)
+	for _, t := range types {
+		if t == actual || t == "number" && actual == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+// The JSON Schema type name of a decoded JSON value.
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+// The path to an element of the value at path, without sharing storage.
+func jsonPath(path []interface{}, elem interface{}) []interface{} {
+	return append(path[:len(path):len(path)], elem)
+}
+
+// Nest err beneath a PropertyError for every key and index of path.
+func jsonPathError(path []interface{}, err error) error {
+	for i := len(path) - 1; i >= 0; i-- {
+		switch elem := path[i].(type) {
+		case string:
+			err = PropertyError{property: elem, err: err}
+		default:
+			err = PropertyError{index: elem, err: err}
+		}
+	}
+	return err
+}
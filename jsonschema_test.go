@@ -0,0 +1,82 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testJSONSchema = `{
+	"type": "object",
+	"required": ["name", "age"],
+	"properties": {
+		"name": {"type": "string", "pattern": "^[a-z]+$"},
+		"age": {"type": "integer", "minimum": 18},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"a/b": {"type": "number"}
+	}
+}`
+
+func TestValidateJSONSchema(t *testing.T) {
+	for _, test := range []struct {
+		instance interface{}
+		fields   []string
+		msg      string
+	}{
+		{map[string]interface{}{"name": "bob", "age": 30, "tags": []string{"x"}}, nil, ""},
+		{map[string]interface{}{"name": "bob", "age": 12}, []string{"age"}, "age: must be at least 18"},
+		{map[string]interface{}{"name": "Bob"}, []string{"age", "name"}, `age: required; name: Invalid does not match ^[a-z]+$: "Bob"`},
+		{map[string]interface{}{"name": "bob", "age": 18.5, "tags": []interface{}{"x", 1}, "a/b": "1"},
+			[]string{"a/b", "age", "tags[1]"},
+			"a/b: must be of type number (is string); age: must be of type integer (is number); tags[1]: must be of type string (is integer)"},
+		{[]int{1}, []string{""}, "must be of type object (is array)"},
+	} {
+		err := ValidateJSONSchema([]byte(testJSONSchema), test.instance)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error %v", test.instance, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%v: expected %q got %v", test.instance, test.msg, err)
+			continue
+		}
+		if fields := InvalidFields(err); !reflect.DeepEqual(fields, test.fields) {
+			t.Errorf("%v: expected fields %q got %q", test.instance, test.fields, fields)
+		}
+	}
+	if err := ValidateJSONSchema([]byte(`{"type": 1}`), 1); err == nil || IsValidationError(err) {
+		t.Errorf("unexpected error for invalid schema %v", err)
+	}
+}
+
+func TestValidateJSONSchemaPointers(t *testing.T) {
+	schema := `{
+		"properties": {
+			"address": {"required": ["zip"], "properties": {"zip": {"type": "string"}}},
+			"phones": {"items": {"properties": {"number": {"type": "string"}}}}
+		}
+	}`
+	instance := map[string]interface{}{
+		"address": map[string]interface{}{},
+		"phones":  []interface{}{map[string]interface{}{"number": "1"}, map[string]interface{}{"number": 2}},
+	}
+	err := ValidateJSONSchema([]byte(schema), instance)
+	expect := map[string]string{
+		"/address/zip":     "required",
+		"/phones/1/number": "must be of type string (is integer)",
+	}
+	if ptrs := PointerErrors(err); !reflect.DeepEqual(ptrs, expect) {
+		t.Errorf("expected %q got %q", expect, ptrs)
+	}
+	if roots := ByRootField(err); len(roots) != 2 || roots["address"] == nil || roots["phones"] == nil {
+		t.Errorf("unexpected root fields %v", roots)
+	}
+	if err := Prefix("User", err); err.Error() != "User.address.zip: required; User.phones[1].number: must be of type string (is integer)" {
+		t.Errorf("unexpected prefixed error %v", err)
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// An error combining several errors, like the result of errors.Join.
+type multiError interface {
+	Unwrap() []error
+}
+
+// Call fn with the full path and originating error of every leaf in err.
+// Errors that do not carry a path are reported with an empty path.
+func eachLeaf(err error, fn func(path string, leaf error)) {
+	walkLeaves("", err, fn)
+}
+
+func walkLeaves(prefix string, err error, fn func(path string, leaf error)) {
+	switch e := err.(type) {
+	case nil:
+	case PropertyError:
+		walkLeaves(e.appendTo(prefix, false), e.err, fn)
+	case contextError:
+		walkLeaves(prefix, e.err, fn)
+	case multiError:
+		for _, child := range e.Unwrap() {
+			walkLeaves(prefix, child, fn)
+		}
+	default:
+		fn(prefix, err)
+	}
+}
+
+// The number of leaves WalkContext visits between checks of its context.
+const walkCheckInterval = 256
+
+// Call fn with the full path and originating error of every leaf in err,
+// like InvalidFields and Render do, until ctx is done. The context is
+// checked before the first leaf and then every 256 leaves; once it is done
+// the walk stops and ctx.Err() is returned.
+func WalkContext(ctx context.Context, err error, fn func(path string, leaf error)) error {
+	n := 0
+	return walkLeavesContext(ctx, "", err, &n, fn)
+}
+
+func walkLeavesContext(ctx context.Context, prefix string, err error, n *int, fn func(path string, leaf error)) error {
+	switch e := err.(type) {
+	case nil:
+	case PropertyError:
+		return walkLeavesContext(ctx, e.appendTo(prefix, false), e.err, n, fn)
+	case contextError:
+		return walkLeavesContext(ctx, prefix, e.err, n, fn)
+	case multiError:
+		for _, child := range e.Unwrap() {
+			if err := walkLeavesContext(ctx, prefix, child, n, fn); err != nil {
+				return err
+			}
+		}
+	default:
+		if *n%walkCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		*n++
+		fn(prefix, err)
+	}
+	return nil
+}
+
+// The full path of the first leaf of err, without rendering any messages.
+// FirstPath returns "" if err is nil or its first leaf has no path.
+//
+//	FirstPath(err) // "Bars[1].Baz"
+func FirstPath(err error) string {
+	path, _ := firstPath("", err)
+	return path
+}
+
+func firstPath(prefix string, err error) (string, bool) {
+	switch e := err.(type) {
+	case nil:
+		return "", false
+	case PropertyError:
+		return firstPath(e.appendTo(prefix, false), e.err)
+	case contextError:
+		return firstPath(prefix, e.err)
+	case multiError:
+		for _, child := range e.Unwrap() {
+			if path, ok := firstPath(prefix, child); ok {
+				return path, true
+			}
+		}
+		return "", false
+	}
+	return prefix, true
+}
+
+// A leaf rendered as "path: message", or its message alone without a path.
+func leafString(path string, leaf error) string {
+	if path == "" {
+		return leaf.Error()
+	}
+	return path + ": " + leafMessage(leaf)
+}
+
+// The sorted, de-duplicated paths of all invalid properties in err. An
+// error without a property, such as one returned directly from a Validate()
+// method, contributes an empty path.
+func InvalidFields(err error) []string {
+	seen := make(map[string]bool)
+	var fields []string
+	eachLeaf(err, func(path string, leaf error) {
+		if !seen[path] {
+			seen[path] = true
+			fields = append(fields, path)
+		}
+	})
+	sort.Strings(fields)
+	return fields
+}
+
+// Re-root err beneath property, so that every leaf path in err gains the
+// property as a prefix. Errors without a path are attributed to property.
+// This also names the root of a top-level collection, whose element paths
+// otherwise begin with a bare index like "[1]".
+//
+//	Prefix("Config", err)           // `Config.Name: required; Config.Port: Invalid: 0`
+//	Prefix("items", V(Bars{b0, b1})) // `items[1].Baz: qux`
+func Prefix(property string, err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case PropertyError:
+		return Nest(property, e)
+	case contextError:
+		return contextError{e.context, Prefix(property, e.err)}
+	case multiError:
+		var errs Errors
+		for _, child := range e.Unwrap() {
+			if child != nil {
+				errs = append(errs, Prefix(property, child))
+			}
+		}
+		return errs.Err()
+	}
+	return NewPropertyError(property, err)
+}
+
+// Render err with every leaf formatted by renderer, joining leaves with
+// "; " like Errors. Leaves with a path are rendered as "path: " followed by
+// the renderer's result.
+//
+//	validate.Render(err, func(path string, leaf error) string {
+//		return strings.ToUpper(leaf.Error())
+//	}) // `Bars[1].Baz: QUX`
+func Render(err error, renderer func(path string, leaf error) string) string {
+	var msgs []string
+	eachLeaf(err, func(path string, leaf error) {
+		msg := renderer(path, leaf)
+		if path != "" {
+			msg = path + ": " + msg
+		}
+		msgs = append(msgs, msg)
+	})
+	return strings.Join(msgs, "; ")
+}
+
+// The marker Truncate appends to a shortened message.
+const truncatedMarker = "… (truncated)"
+
+// The message of err cut so that, followed by "… (truncated)", it is at
+// most maxBytes bytes, for logs and responses with size limits. When even
+// the marker does not fit the cut message is returned without it. The
+// result is never longer than maxBytes, and the message is never cut
+// within a UTF-8 sequence. Only the rendered
+// string is shortened; err and its leaves are unchanged. A maxBytes of 0
+// or less means no limit, and Truncate returns "" for a nil err.
+//
+//	Truncate(err, 1024)
+func Truncate(err error, maxBytes int) string {
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	if maxBytes <= 0 || len(msg) <= maxBytes {
+		return msg
+	}
+	marker := truncatedMarker
+	if len(marker) >= maxBytes {
+		marker = ""
+	}
+	n := maxBytes - len(marker)
+	for n > 0 && !utf8.RuneStart(msg[n]) {
+		n--
+	}
+	return msg[:n] + marker
+}
+
+// Flatten err into its leaves with each leaf path rewritten by mapper, for
+// APIs whose external field names differ from the internal ones. err itself
+// is not modified, so its internal paths remain available for debugging.
+//
+//	MapPaths(err, snakeCase) // `items.1.baz_value: qux`
+func MapPaths(err error, mapper func(path string) string) error {
+	var errs Errors
+	eachLeaf(err, func(path string, leaf error) {
+		if path = mapper(path); path == "" {
+			errs = append(errs, leaf)
+			return
+		}
+		errs = append(errs, PropertyError{property: path, err: leaf})
+	})
+	return errs.Err()
+}
+
+// Drop the root property of err when the property nested directly inside it
+// has the same name, so that naming a value after the field it already
+// reports renders `Email: x` rather than `Email.Email: x`. Collapsing only
+// applies when err is a PropertyError with no index wrapping a single
+// PropertyError; paths like `User.Email` and aggregates are left as they are.
+//
+//	CollapseRoot(Property("Email", email)) // `Email: invalid`
+func CollapseRoot(err error) error {
+	if root, ok := err.(PropertyError); ok && root.index == nil {
+		if inner, ok := root.err.(PropertyError); ok && inner.property == root.property {
+			return inner
+		}
+	}
+	return err
+}
+
+// A stable form of err for golden tests: one "path\tmessage" line per leaf,
+// sorted, so that the order of leaves and the rendering of paths within
+// messages do not matter.
+//
+//	Canonical(err) // "Bars[1].Baz\tqux\nEmail\trequired"
+func Canonical(err error) string {
+	var lines []string
+	eachLeaf(err, func(path string, leaf error) {
+		lines = append(lines, path+"\t"+leafMessage(leaf))
+	})
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// Rebuild err with every leaf passed through fn, which is given the leaf's
+// full path and returns its replacement, such as a friendly message for an
+// internal sentinel error. Paths are kept. A leaf for which fn returns nil
+// is dropped, as if it had passed; RewriteLeaves returns nil if no leaves
+// remain.
+//
+//	RewriteLeaves(err, func(path string, leaf error) error {
+//		if leaf == sql.ErrNoRows {
+//			return errors.New("does not exist")
+//		}
+//		return leaf
+//	})
+func RewriteLeaves(err error, fn func(path string, leaf error) error) error {
+	return mapLeaves(err, fn)
+}
+
+// Rebuild err with every leaf replaced by the result of fn, keeping paths.
+// Leaves for which fn returns nil are dropped.
+func mapLeaves(err error, fn func(path string, leaf error) error) error {
+	return mapLeavesAt("", err, fn)
+}
+
+func mapLeavesAt(prefix string, err error, fn func(path string, leaf error) error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case PropertyError:
+		if e.err = mapLeavesAt(e.appendTo(prefix, false), e.err, fn); e.err == nil {
+			return nil
+		}
+		return e
+	case contextError:
+		if e.err = mapLeavesAt(prefix, e.err, fn); e.err == nil {
+			return nil
+		}
+		return e
+	case multiError:
+		var errs Errors
+		for _, child := range e.Unwrap() {
+			if child = mapLeavesAt(prefix, child, fn); child != nil {
+				errs = append(errs, child)
+			}
+		}
+		return errs.Err()
+	}
+	return fn(prefix, err)
+}
+
+// Group the leaves of err by the first property in their path. Each group
+// is an Errors whose members have paths relative to that property. Leaves
+// without a path are grouped under "", and those of top-level elements
+// under their index, like "[1]".
+//
+//	ByRootField(err)["Address"] // `City: required; Zip: Invalid: "x"`
+func ByRootField(err error) map[string]error {
+	groups := make(map[string]Errors)
+	for _, leaf := range flatten(err) {
+		root, rest := rootField(leaf)
+		groups[root] = append(groups[root], rest)
+	}
+	byRoot := make(map[string]error, len(groups))
+	for root, errs := range groups {
+		byRoot[root] = errs
+	}
+	return byRoot
+}
+
+// The first property of the path of a flattened leaf, and the rest of it.
+// The context of a leaf (see VIn) stays on the rest.
+func rootField(leaf error) (string, error) {
+	switch e := leaf.(type) {
+	case PropertyError:
+		switch {
+		case e.index == nil:
+			return e.property, e.err
+		case e.property == "":
+			return formatIndex(e.index), e.err
+		}
+		return e.property, PropertyError{index: e.index, err: e.err}
+	case contextError:
+		root, rest := rootField(e.err)
+		return root, contextError{e.context, rest}
+	}
+	return "", leaf
+}
+
+// Validate the struct (or struct pointer) v (see V) and report whether each
+// top-level field passed, for indicators that need no messages. Every
+// exported field is a key, valid unless a leaf of the result has its name
+// as the first property of its path; warnings count as failures. Failing
+// roots that are not field names, like the members of a group error, are
+// also reported as invalid. Leaves without a path are not reported.
+//
+//	FieldValidity(signup) // map[Email:false Nickname:true]
+func FieldValidity(v interface{}) map[string]bool {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: FieldValidity of non-struct type %T", v))
+	}
+	validity := make(map[string]bool)
+	for i := 0; i < val.NumField(); i++ {
+		if field := val.Type().Field(i); field.PkgPath == "" {
+			validity[field.Name] = true
+		}
+	}
+	for root := range ByRootField(V(v)) {
+		if root == "" {
+			continue
+		}
+		for _, name := range strings.Split(root, ",") {
+			validity[name] = false
+		}
+	}
+	return validity
+}
@@ -0,0 +1,292 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestInvalidFields(t *testing.T) {
+	nested := Property("Bars", testBars{testBar{1}, testBar{0}, testBar{0}})
+	err := errors.Join(
+		Property("Name", testQux(0)),
+		nested,
+		errors.New("plain"),
+		Property("Name", testQux(0)),
+		Property("Age", testQux(0)),
+	)
+	expect := []string{"", "Age", "Bars[1].Baz", "Bars[2].Baz", "Name"}
+	if fields := InvalidFields(err); !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expected %q got %q", expect, fields)
+	}
+	if fields := InvalidFields(nil); len(fields) != 0 {
+		t.Errorf("unexpected fields %q", fields)
+	}
+	if fields := InvalidFields(errors.New("plain")); !reflect.DeepEqual(fields, []string{""}) {
+		t.Errorf("unexpected fields %q", fields)
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	err := Prefix("Config", Property("Bar", testBar{0}))
+	if msg := err.Error(); msg != "Config.Bar.Baz: qux" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	err = Prefix("Config", Index(2, testBar{0}))
+	if msg := err.Error(); msg != "Config[2].Baz: qux" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	err = Prefix("Config", Errors{Property("Name", testQux(0)), errors.New("plain")})
+	if msg := err.Error(); msg != "Config.Name: qux; Config: plain" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	expect := []string{"Config", "Config.Name"}
+	if fields := InvalidFields(err); !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expected %q got %q", expect, fields)
+	}
+	bars := V(testBars{testBar{1}, testBar{0}})
+	if msg := bars.Error(); msg != "[1].Baz: qux" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if msg := Prefix("items", bars).Error(); msg != "items[1].Baz: qux" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if err := Prefix("Config", nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	err := Errors{
+		PropertyFunc("Bars", func() error { return Index(1, testBar{0}) }),
+		errors.New("plain"),
+	}
+	msg := Render(err, func(path string, leaf error) string {
+		return strings.ToUpper(leaf.Error())
+	})
+	if msg != "Bars[1].Baz: QUX; PLAIN" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if msg := Render(nil, nil); msg != "" {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	err := NewPropertyError("Name", errors.New("ünïcödé"))
+	for _, test := range []struct {
+		max int
+		msg string
+	}{
+		{0, "Name: ünïcödé"},
+		{100, "Name: ünïcödé"},
+		{17, "Name: ünïcödé"},
+		{16, "N" + truncatedMarker},
+		{15, "Name: ünïcöd"},
+		{8, "Name: ü"},
+		{7, "Name: "},
+		{1, "N"},
+	} {
+		msg := Truncate(err, test.max)
+		if msg != test.msg {
+			t.Errorf("%d: expected %q got %q", test.max, test.msg, msg)
+		}
+		if test.max > 0 && len(msg) > test.max {
+			t.Errorf("%d: %d bytes is over the limit", test.max, len(msg))
+		}
+		if !utf8.ValidString(msg) {
+			t.Errorf("%d: invalid UTF-8 %q", test.max, msg)
+		}
+	}
+	long := NewPropertyError("Name", errors.New(strings.Repeat("ü", 20)))
+	for max := 16; max < 46; max++ {
+		msg := Truncate(long, max)
+		if len(msg) > max || !strings.HasSuffix(msg, truncatedMarker) {
+			t.Errorf("%d: unexpected message %q", max, msg)
+		}
+	}
+	if msg := Truncate(long, 24); msg != "Name: ü"+truncatedMarker {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if msg := Truncate(nil, 10); msg != "" {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+func TestMapPaths(t *testing.T) {
+	err := Errors{
+		PropertyFunc("Bars", func() error { return Index(1, testBar{0}) }),
+		errors.New("plain"),
+	}
+	external := strings.NewReplacer("Bars[", "items.", "].Baz", ".baz_value")
+	mapped := MapPaths(err, external.Replace)
+	if mapped == nil || mapped.Error() != "items.1.baz_value: qux; plain" {
+		t.Errorf("unexpected error %v", mapped)
+	}
+	if fields := InvalidFields(mapped); !reflect.DeepEqual(fields, []string{"", "items.1.baz_value"}) {
+		t.Errorf("unexpected fields %q", fields)
+	}
+	if err.Error() != "Bars[1].Baz: qux; plain" {
+		t.Errorf("original error modified: %v", err)
+	}
+	if MapPaths(nil, external.Replace) != nil {
+		t.Errorf("expected nil")
+	}
+}
+
+func TestCollapseRoot(t *testing.T) {
+	x := errors.New("x")
+	for _, test := range []struct {
+		err error
+		msg string
+	}{
+		{NewPropertyError("Email", NewPropertyError("Email", x)), "Email: x"},
+		{NewPropertyError("User", NewPropertyError("Email", x)), "User.Email: x"},
+		{NewPropertyError("Email", elementError("Email", 1, x)), "Email[1]: x"},
+		{elementError("Email", 0, NewPropertyError("Email", x)), "Email[0].Email: x"},
+		{NewPropertyError("Email", x), "Email: x"},
+		{Errors{NewPropertyError("Email", NewPropertyError("Email", x))}, "Email.Email: x"},
+	} {
+		if err := CollapseRoot(test.err); err == nil || err.Error() != test.msg {
+			t.Errorf("expected %q got %v", test.msg, err)
+		}
+	}
+	if CollapseRoot(nil) != nil {
+		t.Errorf("expected nil")
+	}
+}
+
+func TestWalkContext(t *testing.T) {
+	bars := make(testBars, 10000)
+	err := V(bars)
+	var paths []string
+	if e := WalkContext(context.Background(), err, func(path string, leaf error) {
+		paths = append(paths, path)
+	}); e != nil || len(paths) != len(bars) || paths[1] != "[1].Baz" {
+		t.Errorf("unexpected result %v (%d leaves)", e, len(paths))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := 0
+	e := WalkContext(ctx, err, func(path string, leaf error) {
+		if n++; n == 10 {
+			cancel()
+		}
+	})
+	if e != context.Canceled {
+		t.Errorf("expected %v got %v", context.Canceled, e)
+	}
+	if n != walkCheckInterval {
+		t.Errorf("expected walk to stop after %d leaves got %d", walkCheckInterval, n)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	a := Errors{
+		Property("Bars", testBars{testBar{0}, testBar{1}, testBar{0}}),
+		NewPropertyError("Email", errors.New("required")),
+		errors.New("plain"),
+	}
+	b := Errors{
+		errors.New("plain"),
+		NewPropertyError("Email", errors.New("required")),
+		Nest("Bars", Index(2, testBar{0}).(PropertyError)),
+		Nest("Bars", Index(0, testBar{0}).(PropertyError)),
+	}
+	expect := "\tplain\nBars[0].Baz\tqux\nBars[2].Baz\tqux\nEmail\trequired"
+	if c := Canonical(a); c != expect {
+		t.Errorf("expected %q got %q", expect, c)
+	}
+	if Canonical(a) != Canonical(b) {
+		t.Errorf("canonical forms differ:\n%s\n%s", Canonical(a), Canonical(b))
+	}
+	if c := Canonical(nil); c != "" {
+		t.Errorf("unexpected canonical form %q", c)
+	}
+}
+
+func TestFirstPath(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		path string
+	}{
+		{nil, ""},
+		{errors.New("plain"), ""},
+		{Property("Bar", testBar{0}), "Bar.Baz"},
+		{Errors{nil, Property("Bars", testBars{testBar{1}, testBar{0}}), Property("Bar", testBar{0})}, "Bars[1].Baz"},
+		{Errors{Errors{}, errors.New("plain"), Property("Bar", testBar{0})}, ""},
+	} {
+		if path := FirstPath(test.err); path != test.path {
+			t.Errorf("%v: expected %q got %q", test.err, test.path, path)
+		}
+	}
+}
+
+func TestRewriteLeaves(t *testing.T) {
+	errNotFound := errors.New("not found")
+	err := Errors{
+		NewPropertyError("Owner", errNotFound),
+		Property("Bars", testBars{testBar{0}, testBar{1}, testBar{0}}),
+	}
+	friendly := RewriteLeaves(err, func(path string, leaf error) error {
+		switch {
+		case leaf == errNotFound:
+			return errors.New("does not exist")
+		case path == "Bars[2].Baz":
+			return nil
+		}
+		return leaf
+	})
+	if friendly == nil || friendly.Error() != "Owner: does not exist; Bars[0].Baz: qux" {
+		t.Errorf("unexpected error %v", friendly)
+	}
+	suppressed := RewriteLeaves(err, func(string, error) error { return nil })
+	if suppressed != nil {
+		t.Errorf("unexpected error %v", suppressed)
+	}
+}
+
+func TestByRootField(t *testing.T) {
+	err := Errors{
+		Nest("Address", NewPropertyError("City", errors.New("required"))),
+		Property("Name", testQux(0)),
+		Nest("Address", NewPropertyError("Zip", errors.New("invalid"))),
+		PropertyFunc("Bars", func() error { return Index(1, testBar{0}) }),
+		Index(2, testBar{0}),
+		errors.New("plain"),
+	}
+	groups := ByRootField(err)
+	expect := map[string]string{
+		"Address": "City: required; Zip: invalid",
+		"Name":    "qux",
+		"Bars":    "[1].Baz: qux",
+		"[2]":     "Baz: qux",
+		"":        "plain",
+	}
+	if len(groups) != len(expect) {
+		t.Errorf("unexpected groups %v", groups)
+	}
+	for root, msg := range expect {
+		if group := groups[root]; group == nil || group.Error() != msg {
+			t.Errorf("group %q: expected %q got %v", root, msg, group)
+		}
+	}
+}
+
+func TestFieldValidity(t *testing.T) {
+	validity := FieldValidity(testSignup{Nickname: "bob"})
+	if expect := map[string]bool{"Email": false, "Nickname": true}; !reflect.DeepEqual(validity, expect) {
+		t.Errorf("expected %v got %v", expect, validity)
+	}
+	validity = FieldValidity(&testSignup{Email: "a@b.c"})
+	if expect := map[string]bool{"Email": true, "Nickname": true}; !reflect.DeepEqual(validity, expect) {
+		t.Errorf("expected %v got %v", expect, validity)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"log"
+	"log/slog"
+)
+
+// Validate v (see V) and log each failing leaf of the result to logger
+// before returning it. Each leaf is logged on its own line as
+// "validate: path: message", or "validate: message" for a leaf without a
+// path. A nil logger logs nothing.
+func VLog(logger *log.Logger, v interface{}) error {
+	err := V(v)
+	if logger != nil {
+		eachLeaf(err, func(path string, leaf error) {
+			logger.Print("validate: ", leafString(path, leaf))
+		})
+	}
+	return err
+}
+
+// The leaves of err as a log/slog group, one string attribute per path with
+// the path as its key and the message as its value, in the order the paths
+// first appear in err. Leaves without a path have the key "". The messages
+// of leaves sharing a path are joined by "; ", as by PointerErrors, so that
+// no key repeats. A nil err is an empty group.
+//
+//	logger.Error("validation failed", slog.Any("errors", validate.LogValue(err)))
+func LogValue(err error) slog.Value {
+	var attrs []slog.Attr
+	at := make(map[string]int)
+	eachLeaf(err, func(path string, leaf error) {
+		if i, ok := at[path]; ok {
+			attrs[i].Value = slog.StringValue(attrs[i].Value.String() + "; " + leafMessage(leaf))
+			return
+		}
+		at[path] = len(attrs)
+		attrs = append(attrs, slog.String(path, leafMessage(leaf)))
+	})
+	return slog.GroupValue(attrs...)
+}
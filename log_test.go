@@ -0,0 +1,75 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestVLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+	err := VLog(logger, testBars{testBar{0}, testBar{1}, testBar{0}})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	expect := "validate: [0].Baz: qux\nvalidate: [2].Baz: qux\n"
+	if buf.String() != expect {
+		t.Errorf("expected %q got %q", expect, buf.String())
+	}
+	buf.Reset()
+	if err := VLog(logger, testBar{1}); err != nil || buf.Len() != 0 {
+		t.Errorf("unexpected result %v %q", err, buf.String())
+	}
+	if err := VLog(nil, testQux(0)); err == nil || err.Error() != "qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	err := Errors{
+		Property("Bars", testBars{testBar{0}, testBar{1}, testBar{0}}),
+		errors.New("plain"),
+	}
+	value := LogValue(err)
+	if value.Kind() != slog.KindGroup {
+		t.Fatalf("expected a group got %v", value.Kind())
+	}
+	var pairs []string
+	for _, attr := range value.Group() {
+		pairs = append(pairs, attr.Key+"="+attr.Value.String())
+	}
+	if got := strings.Join(pairs, " "); got != "Bars[0].Baz=qux Bars[2].Baz=qux =plain" {
+		t.Errorf("unexpected attributes %q", got)
+	}
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key != "errors" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})).Info("", slog.Any("errors", LogValue(Property("Bar", testBar{0}))))
+	if expect := `{"errors":{"Bar.Baz":"qux"}}` + "\n"; buf.String() != expect {
+		t.Errorf("expected %q got %q", expect, buf.String())
+	}
+	shared := LogValue(Errors{
+		NewPropertyError("Email", ErrRequired),
+		NewPropertyError("Name", ErrRequired),
+		NewPropertyError("Email", errors.New("invalid")),
+	})
+	if group := shared.Group(); len(group) != 2 || group[0].String() != "Email=required; invalid" {
+		t.Errorf("unexpected attributes %v", group)
+	}
+	if LogValue(nil).Group() != nil {
+		t.Errorf("expected an empty group")
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"strings"
+)
+
+// Require that s is a string of digits passing the Luhn checksum. Only the
+// checksum is verified, not that an account with the number exists.
+//
+//	Luhn("79927398713") // nil
+//	Luhn("79927398714") // `Invalid checksum: "79927398714"`
+//	Luhn("7992739871x") // `Invalid not a number: "7992739871x"`
+func Luhn(s string) error {
+	return luhn(s, s)
+}
+
+// Like Luhn but spaces and dashes, as in "4111 1111 1111 1111", are removed
+// first. Errors quote s as given.
+func CreditCard(s string) error {
+	return luhn(strings.NewReplacer(" ", "", "-", "").Replace(s), s)
+}
+
+// Check the digits of s, reporting failures for the input orig.
+func luhn(s, orig string) error {
+	if s == "" || !isDigits(s) {
+		return Invalid("not a number", orig)
+	}
+	sum := 0
+	for i := range s {
+		d := int(s[len(s)-1-i] - '0')
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	if sum%10 != 0 {
+		return Invalid("checksum", orig)
+	}
+	return nil
+}
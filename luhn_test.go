@@ -0,0 +1,39 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+)
+
+func TestLuhn(t *testing.T) {
+	for _, test := range []struct {
+		fn  func(string) error
+		s   string
+		msg string
+	}{
+		{Luhn, "79927398713", ""},
+		{Luhn, "0", ""},
+		{Luhn, "79927398714", `Invalid checksum: "79927398714"`},
+		{Luhn, "7992739871x", `Invalid not a number: "7992739871x"`},
+		{Luhn, "", `Invalid not a number: ""`},
+		{Luhn, "4111 1111 1111 1111", `Invalid not a number: "4111 1111 1111 1111"`},
+		{CreditCard, "4111 1111 1111 1111", ""},
+		{CreditCard, "4111-1111-1111-1111", ""},
+		{CreditCard, "4111-1111-1111-1112", `Invalid checksum: "4111-1111-1111-1112"`},
+		{CreditCard, " - ", `Invalid not a number: " - "`},
+	} {
+		err := test.fn(test.s)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", test.s, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%q: expected %q got %v", test.s, test.msg, err)
+		}
+	}
+}
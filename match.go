@@ -0,0 +1,81 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// Apply leaf to every exported field of the struct (or struct pointer) v
+// whose name matches pattern. Only the fields of v itself are considered,
+// not those of nested structs. All failures are collected into Errors,
+// attributed to the field names, in field order.
+//
+//	VMatch(req, regexp.MustCompile(`ID$`), nonEmpty) // `UserID: required; OrgID: required`
+func VMatch(v interface{}, pattern *regexp.Regexp, leaf func(name string, value interface{}) error) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: VMatch of non-struct type %T", v))
+	}
+	var errs Errors
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" || !pattern.MatchString(field.Name) {
+			continue
+		}
+		value := val.Field(i).Interface()
+		err := PropertyFunc(field.Name, func() error { return leaf(field.Name, value) })
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs.Err()
+}
+
+// Validate only the exported fields of the struct (or struct pointer) new
+// that differ from those of old, as determined by reflect.DeepEqual, with
+// Property. Change detection is shallow: a nested struct field that changed
+// anywhere is validated as a whole through its own Validate() method, and
+// one that did not change is skipped entirely. All failures are collected
+// into Errors. old and new must have the same type; a nil old validates
+// every field.
+func VChanged(old, new interface{}) error {
+	nval := reflect.ValueOf(new)
+	oval := reflect.ValueOf(old)
+	if nval.Kind() == reflect.Ptr {
+		nval = nval.Elem()
+	}
+	if oval.Kind() == reflect.Ptr {
+		oval = oval.Elem()
+	}
+	if nval.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: VChanged of non-struct type %T", new))
+	}
+	if oval.IsValid() && oval.Type() != nval.Type() {
+		panic(fmt.Sprintf("validate: VChanged of differing types %T and %T", old, new))
+	}
+	var errs Errors
+	typ := nval.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		value := nval.Field(i).Interface()
+		if oval.IsValid() && reflect.DeepEqual(oval.Field(i).Interface(), value) {
+			continue
+		}
+		if err := Property(field.Name, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs.Err()
+}
@@ -0,0 +1,73 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestVMatch(t *testing.T) {
+	type request struct {
+		UserID  string
+		OrgID   string
+		Name    string
+		Comment string
+		tokenID string
+	}
+	var checked []string
+	nonEmpty := func(name string, value interface{}) error {
+		checked = append(checked, name)
+		if value.(string) == "" {
+			return errors.New("required")
+		}
+		return nil
+	}
+	req := &request{UserID: "u1"}
+	err := VMatch(req, regexp.MustCompile(`ID$`), nonEmpty)
+	if err == nil || err.Error() != "OrgID: required" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(checked) != 2 || checked[0] != "UserID" || checked[1] != "OrgID" {
+		t.Errorf("unexpected fields checked %q", checked)
+	}
+	req.OrgID = "o1"
+	if err := VMatch(*req, regexp.MustCompile(`ID$`), nonEmpty); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestVChanged(t *testing.T) {
+	type profile struct {
+		Name  testQux
+		Age   testQux
+		Bar   testBar
+		notes string
+	}
+	old := profile{Name: 0, Age: 30, Bar: testBar{1}}
+	updated := old
+	updated.Age = 0
+	err := VChanged(&old, &updated)
+	if err == nil || err.Error() != "Age: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+	updated.Age = 31
+	updated.Bar = testBar{0}
+	err = VChanged(old, updated)
+	if err == nil || err.Error() != "Bar.Baz: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+	updated.Bar = testBar{2}
+	if err := VChanged(old, updated); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := VChanged((*profile)(nil), &updated); err == nil || err.Error() != "Name: qux" {
+		t.Errorf("unexpected error with nil old %v", err)
+	}
+	if err := VChanged(nil, &updated); err == nil || err.Error() != "Name: qux" {
+		t.Errorf("unexpected error with nil old %v", err)
+	}
+}
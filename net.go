@@ -0,0 +1,57 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"net/netip"
+)
+
+// Require that s is a bare IPv4 or IPv6 address. Ports and IPv6 zones, as
+// in "[::1]:80" or "fe80::1%eth0", are not accepted.
+//
+//	IP("10.0.0.256") // `Invalid IP address: "10.0.0.256"`
+func IP(s string) error {
+	if _, ok := parseAddr(s); !ok {
+		return Invalid("IP address", s)
+	}
+	return nil
+}
+
+// Require that s is a bare IPv4 address in dotted decimal form (see IP).
+//
+//	IPv4("::1") // `Invalid IPv4 address: "::1"`
+func IPv4(s string) error {
+	if addr, ok := parseAddr(s); !ok || !addr.Is4() {
+		return Invalid("IPv4 address", s)
+	}
+	return nil
+}
+
+// Require that s is a bare IPv6 address (see IP). IPv4-mapped addresses
+// like "::ffff:10.0.0.1" are IPv6 addresses; "10.0.0.1" is not.
+//
+//	IPv6("10.0.0.1") // `Invalid IPv6 address: "10.0.0.1"`
+func IPv6(s string) error {
+	if addr, ok := parseAddr(s); !ok || !addr.Is6() {
+		return Invalid("IPv6 address", s)
+	}
+	return nil
+}
+
+// Require that s is an IPv4 or IPv6 prefix in CIDR notation, like
+// "10.0.0.0/8". Host bits may be set, as in "10.1.2.3/8".
+//
+//	CIDR("10.0.0.0/33") // `Invalid CIDR prefix: "10.0.0.0/33"`
+func CIDR(s string) error {
+	if _, err := netip.ParsePrefix(s); err != nil {
+		return Invalid("CIDR prefix", s)
+	}
+	return nil
+}
+
+func parseAddr(s string) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(s)
+	return addr, err == nil && addr.Zone() == ""
+}
@@ -0,0 +1,46 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+)
+
+func TestNet(t *testing.T) {
+	for _, test := range []struct {
+		fn  func(string) error
+		s   string
+		msg string
+	}{
+		{IP, "10.0.0.1", ""},
+		{IP, "2001:db8::1", ""},
+		{IP, "10.0.0.256", `Invalid IP address: "10.0.0.256"`},
+		{IP, "fe80::1%eth0", `Invalid IP address: "fe80::1%eth0"`},
+		{IP, "10.0.0.1:80", `Invalid IP address: "10.0.0.1:80"`},
+		{IP, "", `Invalid IP address: ""`},
+		{IPv4, "192.168.1.1", ""},
+		{IPv4, "::1", `Invalid IPv4 address: "::1"`},
+		{IPv4, "::ffff:10.0.0.1", `Invalid IPv4 address: "::ffff:10.0.0.1"`},
+		{IPv6, "::1", ""},
+		{IPv6, "::ffff:10.0.0.1", ""},
+		{IPv6, "10.0.0.1", `Invalid IPv6 address: "10.0.0.1"`},
+		{IPv6, "[::1]", `Invalid IPv6 address: "[::1]"`},
+		{CIDR, "10.0.0.0/8", ""},
+		{CIDR, "2001:db8::/32", ""},
+		{CIDR, "10.0.0.0/33", `Invalid CIDR prefix: "10.0.0.0/33"`},
+		{CIDR, "10.0.0.0", `Invalid CIDR prefix: "10.0.0.0"`},
+	} {
+		err := test.fn(test.s)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", test.s, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%q: expected %q got %v", test.s, test.msg, err)
+		}
+	}
+}
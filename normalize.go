@@ -0,0 +1,47 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"strings"
+)
+
+// Trim leading and trailing white space from *s in place. Trimmed always
+// returns nil so it can run first in a Validate method; the caller's string
+// is modified, so Validate must have a pointer receiver for the trimmed
+// value to be kept.
+//
+//	func (u *User) Validate() error {
+//		Trimmed(&u.Name)
+//		return PropertyRequired("Name", u.Name)
+//	}
+func Trimmed(s *string) error {
+	*s = strings.TrimSpace(*s)
+	return nil
+}
+
+// Implemented by types that canonicalize their own value, like trimming or
+// lower-casing fields and filling in defaults. Normalize must have a pointer
+// receiver to have any effect.
+type Normalizer interface {
+	Normalize()
+}
+
+// Call v.Normalize() if v is a Normalizer. Other values are left as they are.
+func Normalize(v interface{}) {
+	switch v.(type) {
+	case Normalizer:
+		v.(Normalizer).Normalize()
+	}
+}
+
+// Normalize v and then validate it (see V). Normalization always runs
+// first, so Validate sees the canonical value.
+//
+//	err := NormalizeAndValidate(&signup)
+func NormalizeAndValidate(v interface{}) error {
+	Normalize(v)
+	return V(v)
+}
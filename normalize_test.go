@@ -0,0 +1,60 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testHandle struct{ Name string }
+
+func (h *testHandle) Validate() error {
+	Trimmed(&h.Name)
+	return PropertyFunc("Name", func() error {
+		if len(h.Name) > 3 {
+			return fmt.Errorf("must be at most 3 characters (has %d)", len(h.Name))
+		}
+		return nil
+	})
+}
+
+func TestTrimmed(t *testing.T) {
+	h := &testHandle{"  foo  "}
+	if err := V(h); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if h.Name != "foo" {
+		t.Errorf("expected %q got %q", "foo", h.Name)
+	}
+}
+
+type testLogin struct{ Email string }
+
+func (l *testLogin) Normalize() { l.Email = strings.ToLower(l.Email) }
+
+func (l *testLogin) Validate() error {
+	return PropertyFunc("Email", func() error {
+		if l.Email != strings.ToLower(l.Email) {
+			return Invalid("not lower case", l.Email)
+		}
+		return nil
+	})
+}
+
+func TestNormalizeAndValidate(t *testing.T) {
+	if err := V(&testLogin{"Bob@Example.com"}); err == nil {
+		t.Errorf("expected error before normalization")
+	}
+	l := &testLogin{"Bob@Example.com"}
+	if err := NormalizeAndValidate(l); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if l.Email != "bob@example.com" {
+		t.Errorf("unexpected email %q", l.Email)
+	}
+	Normalize(testQux(0)) // not a Normalizer
+}
@@ -0,0 +1,155 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Require that v equals one of the allowed values. Values are compared
+// with ==, so they must be comparable.
+//
+//	OneOf("opne", "open", "closed") // `Invalid: "opne"`
+func OneOf(v interface{}, allowed ...interface{}) error {
+	for _, a := range allowed {
+		if v == a {
+			return nil
+		}
+	}
+	return Invalid(v)
+}
+
+// Like OneOf with the allowed values read from ctx.Value(key), such as the
+// roles the current user may assign. The value must be a []interface{} of
+// allowed values or a map[interface{}]bool set of them. If ctx has no such
+// value, OneOfContext returns an error that is not a validation error.
+//
+//	ctx = context.WithValue(ctx, assignableRoles, []interface{}{"editor", "viewer"})
+//	OneOfContext(ctx, assignableRoles, "admin") // `Invalid: "admin"`
+func OneOfContext(ctx context.Context, key interface{}, v interface{}) error {
+	switch allowed := ctx.Value(key).(type) {
+	case []interface{}:
+		return OneOf(v, allowed...)
+	case map[interface{}]bool:
+		if !allowed[v] {
+			return Invalid(v)
+		}
+		return nil
+	}
+	return internalError{fmt.Errorf("validate: no allowed values in context for key %v", key)}
+}
+
+// A check that a string is in the set returned by load, for allow-lists too
+// large to load at init. The set is loaded on the first call of the check,
+// at most once even under concurrent use, and reused by every later call.
+// If load fails, that call and every later one returns the load error,
+// wrapped so that it is not a validation error, even attributed to a
+// property; load is not retried.
+//
+//	validCode := OneOfLazy(loadCodes)
+//	validCode("ZZ9") // `Invalid: "ZZ9"`
+func OneOfLazy(load func() (map[string]bool, error)) func(v string) error {
+	var (
+		once    sync.Once
+		allowed map[string]bool
+		loadErr error
+	)
+	return func(v string) error {
+		once.Do(func() {
+			if allowed, loadErr = load(); loadErr != nil {
+				loadErr = internalError{fmt.Errorf("validate: loading allowed values: %w", loadErr)}
+			}
+		})
+		if loadErr != nil {
+			return loadErr
+		}
+		if !allowed[v] {
+			return Invalid(v)
+		}
+		return nil
+	}
+}
+
+// Require that v is a defined member of a typed enum. Unlike OneOf, the
+// members are a set meant to live next to the enum's constants, so the
+// enum's definition is the single source of truth.
+//
+//	var validStatuses = map[Status]bool{Active: true, Suspended: true}
+//	Enum(Status(7), validStatuses) // `Invalid: 7`
+func Enum[T comparable](v T, valid map[T]bool) error {
+	if !valid[v] {
+		return Invalid(v)
+	}
+	return nil
+}
+
+// Like Enum with the members given as a slice.
+func EnumOf[T comparable](v T, members []T) error {
+	for _, m := range members {
+		if v == m {
+			return nil
+		}
+	}
+	return Invalid(v)
+}
+
+// Like OneOf for strings, but when s is not allowed the message suggests
+// the allowed value closest to s by edit distance. Nothing is suggested
+// when even the closest value differs from s in more than half of its
+// characters.
+//
+//	OneOfSuggest("opne", "open", "closed") // `Invalid: "opne" (did you mean "open"?)`
+func OneOfSuggest(s string, allowed ...string) error {
+	best, bestDist := "", -1
+	for _, a := range allowed {
+		if s == a {
+			return nil
+		}
+		if d := levenshtein(s, a); bestDist < 0 || d < bestDist {
+			best, bestDist = a, d
+		}
+	}
+	inv := Invalid(s).(InvalidError)
+	if bestDist < 0 || 2*bestDist > len([]rune(best)) {
+		return inv
+	}
+	return suggestionError{inv, best}
+}
+
+type suggestionError struct {
+	InvalidError
+	suggestion string
+}
+
+func (err suggestionError) Error() string {
+	return fmt.Sprintf("%v (did you mean %q?)", err.InvalidError, err.suggestion)
+}
+
+func (err suggestionError) Unwrap() error { return err.InvalidError }
+
+// The edit distance between a and b, counting rune insertions, deletions
+// and substitutions.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,158 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestOneOf(t *testing.T) {
+	if err := OneOf("open", "open", "closed"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := OneOf(3, 1, 2, 3); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := OneOf("opne", "open", "closed"); err == nil || err.Error() != `Invalid: "opne"` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestOneOfLazy(t *testing.T) {
+	loads := 0
+	check := OneOfLazy(func() (map[string]bool, error) {
+		loads++
+		return map[string]bool{"AB1": true, "CD2": true}, nil
+	})
+	if loads != 0 {
+		t.Errorf("loaded before first use")
+	}
+	if err := check("AB1"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := check("ZZ9"); err == nil || err.Error() != `Invalid: "ZZ9"` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := check("CD2"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if loads != 1 {
+		t.Errorf("expected 1 load got %d", loads)
+	}
+
+	errDisk := errors.New("disk error")
+	loads = 0
+	check = OneOfLazy(func() (map[string]bool, error) {
+		loads++
+		return nil, errDisk
+	})
+	for i := 0; i < 2; i++ {
+		err := check("AB1")
+		if !errors.Is(err, errDisk) || IsValidationError(err) {
+			t.Errorf("unexpected error %v", err)
+		}
+		err = PropertyFunc("Code", func() error { return check("AB1") })
+		if err == nil || IsValidationError(err) {
+			t.Errorf("unexpected property error %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected 1 load got %d", loads)
+	}
+}
+
+func TestOneOfSuggest(t *testing.T) {
+	allowed := []string{"open", "closed", "pending"}
+	if err := OneOfSuggest("closed", allowed...); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	for _, test := range []struct{ s, msg string }{
+		{"opne", `Invalid: "opne" (did you mean "open"?)`},
+		{"clsed", `Invalid: "clsed" (did you mean "closed"?)`},
+		{"pendign", `Invalid: "pendign" (did you mean "pending"?)`},
+		{"xyz", `Invalid: "xyz"`},
+		{"", `Invalid: ""`},
+	} {
+		err := OneOfSuggest(test.s, allowed...)
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%q: expected %q got %v", test.s, test.msg, err)
+		}
+	}
+	if err := OneOfSuggest("a"); err == nil || err.Error() != `Invalid: "a"` {
+		t.Errorf("unexpected error with nothing allowed %v", err)
+	}
+	if msg := Safe(NewPropertyError("State", OneOfSuggest("opne", allowed...))).Error(); msg != "State: Invalid" {
+		t.Errorf("unexpected safe message %q", msg)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		d    int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"opne", "open", 2},
+		{"héllo", "hello", 1},
+	} {
+		if d := levenshtein(test.a, test.b); d != test.d {
+			t.Errorf("levenshtein(%q, %q): expected %d got %d", test.a, test.b, test.d, d)
+		}
+	}
+}
+
+type testStatus int
+
+const (
+	testActive testStatus = iota + 1
+	testSuspended
+)
+
+var testStatuses = map[testStatus]bool{testActive: true, testSuspended: true}
+
+func TestEnum(t *testing.T) {
+	if err := Enum(testSuspended, testStatuses); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Enum(testStatus(7), testStatuses); err == nil || err.Error() != "Invalid: 7" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := EnumOf(testActive, []testStatus{testActive, testSuspended}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := EnumOf(testStatus(0), []testStatus{testActive, testSuspended}); err == nil || err.Error() != "Invalid: 0" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+type testContextKey string
+
+func TestOneOfContext(t *testing.T) {
+	key := testContextKey("roles")
+	for _, allowed := range []interface{}{
+		[]interface{}{"editor", "viewer"},
+		map[interface{}]bool{"editor": true, "viewer": true},
+	} {
+		ctx := context.WithValue(context.Background(), key, allowed)
+		if err := OneOfContext(ctx, key, "viewer"); err != nil {
+			t.Errorf("%T: unexpected error %v", allowed, err)
+		}
+		if err := OneOfContext(ctx, key, "admin"); err == nil || err.Error() != `Invalid: "admin"` {
+			t.Errorf("%T: unexpected error %v", allowed, err)
+		}
+	}
+	err := OneOfContext(context.Background(), key, "viewer")
+	if err == nil || IsValidationError(err) {
+		t.Errorf("unexpected error for missing set %v", err)
+	}
+	err = PropertyFunc("Role", func() error { return OneOfContext(context.Background(), key, "viewer") })
+	if err == nil || IsValidationError(err) {
+		t.Errorf("unexpected property error for missing set %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+// Implemented by types whose validity depends on data from the value
+// containing them. The parent data is read-only context passed down by
+// PropertyWith or IndexWith.
+//
+//	type LineItem struct { Currency string }
+//	func (item LineItem) ValidateWith(parent interface{}) error {
+//		if item.Currency != parent.(*Order).Currency {
+//			return validator.Property("Currency", validator.Invalid(item.Currency))
+//		}
+//		return nil
+//	}
+type ParentInterface interface {
+	ValidateWith(parent interface{}) error
+}
+
+// Call ValidateWith(parent) on v if v implements ParentInterface, otherwise
+// validate v as V does.
+func VWith(parent, v interface{}) error {
+	switch v.(type) {
+	case ParentInterface:
+		return v.(ParentInterface).ValidateWith(parent)
+	}
+	return V(v)
+}
+
+// Validate a property value, giving it access to parent data (see
+// ParentInterface).
+func PropertyWith(property string, parent, value interface{}) error {
+	return PropertyFunc(property, func() error { return VWith(parent, value) })
+}
+
+// Validate an element value, giving it access to parent data (see
+// ParentInterface).
+func IndexWith(index, parent, value interface{}) error {
+	return IndexFunc(index, func() error { return VWith(parent, value) })
+}
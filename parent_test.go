@@ -0,0 +1,58 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+)
+
+type testOrder struct {
+	Currency string
+	Items    []testLineItem
+}
+
+func (order *testOrder) Validate() error {
+	return PropertyFunc("Items", func() error {
+		for i, item := range order.Items {
+			if err := IndexWith(i, order, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type testLineItem struct{ Currency string }
+
+func (item testLineItem) ValidateWith(parent interface{}) error {
+	if item.Currency != parent.(*testOrder).Currency {
+		return PropertyFunc("Currency", func() error { return Invalid("currency", item.Currency) })
+	}
+	return nil
+}
+
+func TestPropertyWith(t *testing.T) {
+	order := &testOrder{"USD", []testLineItem{{"USD"}, {"EUR"}}}
+	err := V(order)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if prop := err.(PropertyError).Property(); prop != "Items[1].Currency" {
+		t.Errorf("unexpected property %q", prop)
+	}
+	if msg := err.Error(); msg != `Items[1].Currency: Invalid currency: "EUR"` {
+		t.Errorf("unexpected message %q", msg)
+	}
+	order.Items[1].Currency = "USD"
+	if err := V(order); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := PropertyWith("Item", order, testLineItem{"GBP"}); err == nil {
+		t.Errorf("expected an error")
+	}
+	if err := PropertyWith("Qux", order, testQux(0)); err == nil || err.Error() != "Qux: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse s as a base 10 integer and check that it lies in [min, max].
+//
+//	IntString("abc", 1, 10) // `Invalid not a number: "abc"`
+//	IntString("12", 1, 10)  // `Invalid out of range [1, 10]: "12"`
+func IntString(s string, min, max int) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		if isRangeError(err) {
+			return Invalid(outOfRange(min, max), s)
+		}
+		return Invalid("not a number", s)
+	}
+	if n < min || n > max {
+		return Invalid(outOfRange(min, max), s)
+	}
+	return nil
+}
+
+// Parse s as a floating point number and check that it lies in [min, max]
+// (see IntString).
+func FloatString(s string, min, max float64) error {
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		if isRangeError(err) {
+			return Invalid(outOfRange(min, max), s)
+		}
+		return Invalid("not a number", s)
+	}
+	if x < min || x > max || x != x {
+		return Invalid(outOfRange(min, max), s)
+	}
+	return nil
+}
+
+// Check that s is a decimal number with at most precision digits, of which
+// at most scale follow the decimal point, without parsing it as a float. s
+// may have a leading '+' or '-' sign, which is not counted. Leading zeros
+// of the integer part are not counted toward precision; every fractional
+// digit, including trailing zeros, counts toward both precision and scale.
+//
+//	Decimal("-1234.50", 10, 2) // nil
+//	Decimal("0012.345", 10, 2) // `Invalid more than 2 decimal places: "0012.345"`
+//	Decimal("1e5", 10, 2)      // `Invalid not a decimal: "1e5"`
+func Decimal(s string, precision, scale int) error {
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 {
+		return Invalid("not a decimal", s)
+	}
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return Invalid("not a decimal", s)
+	}
+	if len(frac) > scale {
+		return Invalid(fmt.Sprintf("more than %d decimal places", scale), s)
+	}
+	if len(strings.TrimLeft(whole, "0"))+len(frac) > precision {
+		return Invalid(fmt.Sprintf("more than %d digits", precision), s)
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func outOfRange(min, max interface{}) string {
+	return fmt.Sprintf("out of range [%v, %v]", min, max)
+}
+
+func isRangeError(err error) bool {
+	switch err.(type) {
+	case *strconv.NumError:
+		return err.(*strconv.NumError).Err == strconv.ErrRange
+	}
+	return false
+}
+
+// Decimal and grouping separators used to parse numbers for a locale.
+type Locale struct {
+	Decimal  rune
+	Grouping rune
+}
+
+var (
+	LocaleUS = Locale{Decimal: '.', Grouping: ','} // 1,234.56
+	LocaleEU = Locale{Decimal: ',', Grouping: '.'} // 1.234,56
+)
+
+// Like IntString but s may contain the locale's grouping separator.
+//
+//	LocaleEU.IntString("1.234", 0, 5000) // nil
+func (l Locale) IntString(s string, min, max int) error {
+	t, ok := l.normalize(s)
+	if !ok {
+		return Invalid("not a number", s)
+	}
+	if err := IntString(t, min, max); err != nil {
+		return l.invalid(err, s)
+	}
+	return nil
+}
+
+// Like FloatString but s uses the locale's decimal and grouping separators.
+//
+//	LocaleEU.FloatString("1.234,56", 0, 5000) // nil
+//	LocaleUS.FloatString("1,234.56", 0, 5000) // nil
+func (l Locale) FloatString(s string, min, max float64) error {
+	t, ok := l.normalize(s)
+	if !ok {
+		return Invalid("not a number", s)
+	}
+	if err := FloatString(t, min, max); err != nil {
+		return l.invalid(err, s)
+	}
+	return nil
+}
+
+// Rewrite s using '.' as the decimal separator and without grouping. Grouping
+// after the decimal separator, or a '.' that is not one of the locale's
+// separators, makes s invalid.
+func (l Locale) normalize(s string) (string, bool) {
+	b := make([]rune, 0, len(s))
+	decimal := false
+	for _, r := range s {
+		switch {
+		case r == l.Grouping:
+			if decimal {
+				return "", false
+			}
+		case r == l.Decimal:
+			decimal = true
+			b = append(b, '.')
+		case r == '.':
+			return "", false
+		default:
+			b = append(b, r)
+		}
+	}
+	return string(b), true
+}
+
+// Report err from a normalized parse against the original input s.
+func (l Locale) invalid(err error, s string) error {
+	inv := err.(InvalidError)
+	inv.value, inv.hasValue = s, true
+	return inv
+}
@@ -0,0 +1,129 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+)
+
+func TestIntString(t *testing.T) {
+	for _, test := range []struct {
+		s   string
+		msg string
+	}{
+		{"5", ""},
+		{"1", ""},
+		{"10", ""},
+		{"abc", `Invalid not a number: "abc"`},
+		{"", `Invalid not a number: ""`},
+		{"0", `Invalid out of range [1, 10]: "0"`},
+		{"11", `Invalid out of range [1, 10]: "11"`},
+		{"99999999999999999999", `Invalid out of range [1, 10]: "99999999999999999999"`},
+	} {
+		err := IntString(test.s, 1, 10)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("IntString(%q): unexpected error %v", test.s, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("IntString(%q): expected %q got %v", test.s, test.msg, err)
+		}
+	}
+}
+
+func TestFloatString(t *testing.T) {
+	for _, test := range []struct {
+		s   string
+		msg string
+	}{
+		{"0.5", ""},
+		{"1", ""},
+		{"x.5", `Invalid not a number: "x.5"`},
+		{"1.5", `Invalid out of range [0, 1]: "1.5"`},
+		{"-0.1", `Invalid out of range [0, 1]: "-0.1"`},
+		{"NaN", `Invalid out of range [0, 1]: "NaN"`},
+	} {
+		err := FloatString(test.s, 0, 1)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("FloatString(%q): unexpected error %v", test.s, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("FloatString(%q): expected %q got %v", test.s, test.msg, err)
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	for _, test := range []struct {
+		locale Locale
+		s      string
+		msg    string
+	}{
+		{LocaleEU, "1.234,56", ""},
+		{LocaleUS, "1,234.56", ""},
+		{LocaleEU, "0,5", ""},
+		{LocaleUS, "1.234,56", `Invalid not a number: "1.234,56"`},
+		{LocaleEU, "1.234,56.7", `Invalid not a number: "1.234,56.7"`},
+		{LocaleEU, "6.000", `Invalid out of range [0, 5000]: "6.000"`},
+		{Locale{Decimal: ',', Grouping: ' '}, "1.5", `Invalid not a number: "1.5"`},
+		{Locale{Decimal: ',', Grouping: ' '}, "1 234,5", ""},
+	} {
+		err := test.locale.FloatString(test.s, 0, 5000)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%+v.FloatString(%q): unexpected error %v", test.locale, test.s, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%+v.FloatString(%q): expected %q got %v", test.locale, test.s, test.msg, err)
+		}
+	}
+	if err := LocaleEU.IntString("1.234", 0, 5000); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := LocaleUS.IntString("1.234", 0, 5000); err == nil {
+		t.Errorf("expected error for decimal integer")
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	for _, test := range []struct {
+		s   string
+		msg string
+	}{
+		{"1234.56", ""},
+		{"-1234.50", ""},
+		{"+0.5", ""},
+		{"0001234.5", ""},
+		{".5", ""},
+		{"5.", ""},
+		{"12345678.90", ""},
+		{"1.234", `Invalid more than 2 decimal places: "1.234"`},
+		{"123456789.10", `Invalid more than 10 digits: "123456789.10"`},
+		{"-1234567890.1", `Invalid more than 10 digits: "-1234567890.1"`},
+		{"", `Invalid not a decimal: ""`},
+		{".", `Invalid not a decimal: "."`},
+		{"--1", `Invalid not a decimal: "--1"`},
+		{"1e5", `Invalid not a decimal: "1e5"`},
+		{"1.2.3", `Invalid not a decimal: "1.2.3"`},
+	} {
+		err := Decimal(test.s, 10, 2)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("Decimal(%q): unexpected error %v", test.s, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("Decimal(%q): expected %q got %v", test.s, test.msg, err)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+// Validate each value received from in. Errors are indexed by the ordinal
+// of the offending value and sent on the returned channel, which is closed
+// once in is closed. Valid values produce nothing.
+//
+// The returned channel is unbuffered, so a slow consumer slows validation.
+//
+//	errs := validate.VPipe(records)
+//	for err := range errs {
+//		log.Print(err) // `[3]: qux`
+//	}
+func VPipe(in <-chan interface{}) <-chan error {
+	out := make(chan error)
+	go func() {
+		defer close(out)
+		i := 0
+		for v := range in {
+			if err := Index(i, v); err != nil {
+				out <- err
+			}
+			i++
+		}
+	}()
+	return out
+}
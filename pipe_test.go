@@ -0,0 +1,32 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+)
+
+func TestVPipe(t *testing.T) {
+	in := make(chan interface{})
+	go func() {
+		for _, v := range []interface{}{testQux(1), testQux(0), "skip", testQux(0), testQux(2)} {
+			in <- v
+		}
+		close(in)
+	}()
+	var msgs []string
+	for err := range VPipe(in) {
+		msgs = append(msgs, err.Error())
+	}
+	expect := []string{"[1]: qux", "[3]: qux"}
+	if len(msgs) != len(expect) {
+		t.Fatalf("expected %q got %q", expect, msgs)
+	}
+	for i := range expect {
+		if msgs[i] != expect[i] {
+			t.Errorf("error %d: expected %q got %q", i, expect[i], msgs[i])
+		}
+	}
+}
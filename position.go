@@ -0,0 +1,67 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+)
+
+// A line and column in a source document, as tracked by decoders.
+type Position struct {
+	Line, Column int
+}
+
+func (pos Position) String() string {
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+}
+
+// A leaf validation error that carries the source position of the invalid
+// value. It renders exactly like the error it wraps.
+type PositionError struct {
+	Position Position
+	Err      error
+}
+
+// Attach a source position to a leaf validation error. Decoders that track
+// positions can use this when validating decoded values.
+func AtPosition(line, column int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return PositionError{Position{line, column}, err}
+}
+
+func (err PositionError) Error() string { return err.Err.Error() }
+func (err PositionError) Unwrap() error { return err.Err }
+
+// The source position of the error originating err, if any.
+func (err PropertyError) Position() (Position, bool) {
+	return positionOf(err.leaf())
+}
+
+func positionOf(err error) (Position, bool) {
+	switch err.(type) {
+	case PositionError:
+		return err.(PositionError).Position, true
+	case PropertyError:
+		return err.(PropertyError).Position()
+	}
+	return Position{}, false
+}
+
+// Render err prefixed with a file name and, when known, the source position
+// of the invalid value.
+//
+//	FormatPosition("config.yaml", err) // `config.yaml:12:5: timeout: must be positive`
+func FormatPosition(filename string, err error) string {
+	prefix := filename
+	if pos, ok := positionOf(err); ok {
+		prefix = fmt.Sprintf("%s:%v", prefix, pos)
+	}
+	if prefix == "" {
+		return err.Error()
+	}
+	return fmt.Sprintf("%s: %v", prefix, err)
+}
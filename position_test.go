@@ -0,0 +1,47 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+type testTimeout struct {
+	seconds   int
+	line, col int
+}
+
+func (timeout testTimeout) Validate() error {
+	if timeout.seconds <= 0 {
+		return AtPosition(timeout.line, timeout.col, errors.New("must be positive"))
+	}
+	return nil
+}
+
+func TestFormatPosition(t *testing.T) {
+	err := Property("timeout", testTimeout{0, 12, 5})
+	if msg := err.Error(); msg != "timeout: must be positive" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	pos, ok := err.(PropertyError).Position()
+	if !ok || pos != (Position{12, 5}) {
+		t.Errorf("unexpected position %v (%v)", pos, ok)
+	}
+	if msg := FormatPosition("config.yaml", err); msg != "config.yaml:12:5: timeout: must be positive" {
+		t.Errorf("unexpected formatted message %q", msg)
+	}
+
+	err = Property("timeout", testQux(0))
+	if _, ok := err.(PropertyError).Position(); ok {
+		t.Errorf("unexpected position")
+	}
+	if msg := FormatPosition("", err); msg != "timeout: qux" {
+		t.Errorf("unexpected formatted message %q", msg)
+	}
+	if msg := FormatPosition("config.yaml", err); msg != "config.yaml: timeout: qux" {
+		t.Errorf("unexpected formatted message %q", msg)
+	}
+}
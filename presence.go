@@ -0,0 +1,126 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Require that at least one property of a group is present. present[i]
+// tells whether properties[i] was given. The error is attributed to the
+// whole group.
+//
+//	AtLeastOne([]string{"Phone", "Email"}, []bool{false, false}) // `Phone,Email: at least one is required`
+func AtLeastOne(properties []string, present []bool) error {
+	if countPresent(properties, present) == 0 {
+		return groupError(properties, errors.New("at least one is required"))
+	}
+	return nil
+}
+
+// Require that exactly one property of a group is present (see AtLeastOne).
+//
+//	ExactlyOne([]string{"Phone", "Email"}, []bool{true, true}) // `Phone,Email: exactly one is required (2 given)`
+func ExactlyOne(properties []string, present []bool) error {
+	if n := countPresent(properties, present); n != 1 {
+		return groupError(properties, fmt.Errorf("exactly one is required (%d given)", n))
+	}
+	return nil
+}
+
+// Require that a group of mutually dependent properties is given together:
+// all present or all absent (see AtLeastOne). Only a partial group fails,
+// and the error is attributed to the whole group.
+//
+//	AllOrNone([]string{"Street", "City", "Zip"}, []bool{true, false, true}) // `Street,City,Zip: all or none are required (2 of 3 given)`
+func AllOrNone(properties []string, present []bool) error {
+	if n := countPresent(properties, present); n != 0 && n != len(properties) {
+		return groupError(properties, fmt.Errorf("all or none are required (%d of %d given)", n, len(properties)))
+	}
+	return nil
+}
+
+// Require that exactly one of the named fields of the struct (or struct
+// pointer) v is non-empty, that is not its zero value. With zero or many
+// non-empty fields the error is attributed to all of the named fields, as
+// with ExactlyOne. A field promoted from an embedded struct pointer that is
+// nil does not exist, so it counts as empty.
+//
+//	VExactlyOneGroup(contact, "Phone", "Email") // `Phone,Email: exactly one is required (0 given)`
+func VExactlyOneGroup(v interface{}, fields ...string) error {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: VExactlyOneGroup of non-struct type %T", v))
+	}
+	present := make([]bool, len(fields))
+	for i, name := range fields {
+		sf, ok := val.Type().FieldByName(name)
+		if !ok {
+			panic(fmt.Sprintf("validate: VExactlyOneGroup of unknown field %q", name))
+		}
+		if field, err := val.FieldByIndexErr(sf.Index); err == nil {
+			present[i] = !field.IsZero()
+		}
+	}
+	return ExactlyOne(fields, present)
+}
+
+// Report the failures of required, the cheap presence checks of a type, if
+// there are any; otherwise run and report the more expensive checks. A
+// mostly empty form then fails fast with what is required, without running
+// format checks on the few fields that were filled in.
+//
+//	return validator.RequiredFirst(validator.Errors{
+//		validator.PropertyRequired("Email", u.Email),
+//		validator.PropertyRequired("Name", u.Name),
+//	}.Err(), func() error {
+//		return validator.PropertyFunc("Email", func() error { return checkMX(u.Email) })
+//	})
+func RequiredFirst(required error, checks func() error) error {
+	if required != nil {
+		return required
+	}
+	return checks()
+}
+
+// The error reported by RequirePresent for fields absent from the input.
+var ErrMissing = errors.New("missing")
+
+// Require that each of fields was present in the input, as recorded by a
+// decoder in present (see PresentFields). Unlike PropertyRequired this does
+// not look at values, so a field given its zero value is present; this
+// tells a PATCH that omits a field apart from one that clears it.
+//
+//	RequirePresent(map[string]bool{"name": true}, "name", "email") // `email: missing`
+func RequirePresent(present map[string]bool, fields ...string) error {
+	var errs Errors
+	for _, field := range fields {
+		if !present[field] {
+			errs = append(errs, PropertyError{property: field, err: ErrMissing})
+		}
+	}
+	return errs.Err()
+}
+
+func countPresent(properties []string, present []bool) int {
+	if len(properties) != len(present) {
+		panic("validate: properties and present differ in length")
+	}
+	n := 0
+	for _, p := range present {
+		if p {
+			n++
+		}
+	}
+	return n
+}
+
+// An error attributed to several properties at once.
+func groupError(properties []string, err error) error {
+	return PropertyError{property: strings.Join(properties, ","), err: err}
+}
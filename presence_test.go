@@ -0,0 +1,134 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAtLeastOne(t *testing.T) {
+	props := []string{"Phone", "Email"}
+	err := AtLeastOne(props, []bool{false, false})
+	if err == nil || err.Error() != "Phone,Email: at least one is required" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := AtLeastOne(props, []bool{false, true}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := AtLeastOne(props, []bool{true, true}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestExactlyOne(t *testing.T) {
+	props := []string{"Phone", "Email"}
+	err := ExactlyOne(props, []bool{false, false})
+	if err == nil || err.Error() != "Phone,Email: exactly one is required (0 given)" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := ExactlyOne(props, []bool{true, false}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = ExactlyOne(props, []bool{true, true})
+	if err == nil || err.Error() != "Phone,Email: exactly one is required (2 given)" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestVExactlyOneGroup(t *testing.T) {
+	type contact struct {
+		Phone string
+		Email string
+		Fax   *string
+	}
+	fax := ""
+	for _, test := range []struct {
+		v   interface{}
+		msg string
+	}{
+		{contact{}, "Phone,Email,Fax: exactly one is required (0 given)"},
+		{contact{Email: "a@example.com"}, ""},
+		{&contact{Fax: &fax}, ""},
+		{contact{Phone: "555", Email: "a@example.com"}, "Phone,Email,Fax: exactly one is required (2 given)"},
+	} {
+		err := VExactlyOneGroup(test.v, "Phone", "Email", "Fax")
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error %v", test.v, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%+v: expected %q got %v", test.v, test.msg, err)
+		}
+	}
+}
+
+func TestVExactlyOneGroupEmbedded(t *testing.T) {
+	type base struct{ Phone string }
+	type contact struct {
+		*base
+		Email string
+	}
+	err := VExactlyOneGroup(contact{}, "Phone", "Email")
+	if err == nil || err.Error() != "Phone,Email: exactly one is required (0 given)" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := VExactlyOneGroup(contact{Email: "a@example.com"}, "Phone", "Email"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = VExactlyOneGroup(contact{&base{"555"}, "a@example.com"}, "Phone", "Email")
+	if err == nil || err.Error() != "Phone,Email: exactly one is required (2 given)" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestRequirePresent(t *testing.T) {
+	present, err := PresentFields([]byte(`{"name": "", "email": "a@example.com"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RequirePresent(present, "name", "email"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = RequirePresent(present, "name", "phone", "fax")
+	if err == nil || err.Error() != "phone: missing; fax: missing" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := PresentFields([]byte(`[1]`)); err == nil {
+		t.Errorf("expected error for non-object")
+	}
+}
+
+func TestRequiredFirst(t *testing.T) {
+	calls := 0
+	spy := func() error {
+		calls++
+		return NewPropertyError("Email", errors.New("no MX record"))
+	}
+	err := RequiredFirst(Errors{PropertyRequired("Email", ""), PropertyRequired("Name", "")}.Err(), spy)
+	if err == nil || err.Error() != "Email: required; Name: required" || calls != 0 {
+		t.Errorf("unexpected error %v (%d calls)", err, calls)
+	}
+	err = RequiredFirst(Errors{PropertyRequired("Email", "a@b"), PropertyRequired("Name", "a")}.Err(), spy)
+	if err == nil || err.Error() != "Email: no MX record" || calls != 1 {
+		t.Errorf("unexpected error %v (%d calls)", err, calls)
+	}
+}
+
+func TestAllOrNone(t *testing.T) {
+	props := []string{"Street", "City", "Zip"}
+	if err := AllOrNone(props, []bool{true, true, true}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := AllOrNone(props, []bool{false, false, false}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := AllOrNone(props, []bool{true, false, true})
+	if err == nil || err.Error() != "Street,City,Zip: all or none are required (2 of 3 given)" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
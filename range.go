@@ -0,0 +1,119 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Check that v lies between lo and hi, each bound inclusive or exclusive.
+// The message writes the range in interval notation, a bracket for an
+// inclusive bound and a parenthesis for an exclusive one, like IntString.
+//
+//	RangeEx(0.0, 0.0, 1.0, false, true) // 0 < v <= 1: `Invalid out of range (0, 1]: 0`
+//	RangeEx(1.0, 0.0, 1.0, true, false) // 0 <= v < 1: `Invalid out of range [0, 1): 1`
+//	RangeEx(0.0, 0.0, 1.0, false, false) // 0 < v < 1: `Invalid out of range (0, 1): 0`
+//	RangeEx(1.0, 0.0, 1.0, true, true)  // 0 <= v <= 1: nil
+func RangeEx[T cmp.Ordered](v, lo, hi T, loInclusive, hiInclusive bool) error {
+	loOK := v > lo || loInclusive && v == lo
+	hiOK := v < hi || hiInclusive && v == hi
+	if loOK && hiOK {
+		return nil
+	}
+	open, close := "(", ")"
+	if loInclusive {
+		open = "["
+	}
+	if hiInclusive {
+		close = "]"
+	}
+	return Invalid(fmt.Sprintf("out of range %s%v, %v%s", open, lo, hi, close), v)
+}
+
+// Require that property did not go backwards from old to new, as for
+// version counters. With strict, new must be greater than old; otherwise it
+// may also equal old.
+//
+//	Monotonic("Version", 3, 3, true)  // `Version: must increase`
+//	Monotonic("Version", 3, 2, false) // `Version: must not decrease`
+func Monotonic[T cmp.Ordered](property string, old, new T, strict bool) error {
+	switch {
+	case strict && !(new > old):
+		return NewPropertyError(property, errMustIncrease)
+	case !strict && new < old:
+		return NewPropertyError(property, errMustNotDecrease)
+	}
+	return nil
+}
+
+var (
+	errMustIncrease    = errors.New("must increase")
+	errMustNotDecrease = errors.New("must not decrease")
+)
+
+// The integer and floating-point types, and types based on them.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// The tolerance, relative to the step, within which MultipleOf accepts a
+// floating point value as a multiple.
+const multipleTolerance = 1e-9
+
+// Require that v is a whole multiple of step, as for prices in cents or
+// quantities sold in packs. Floating point values are accepted within
+// 1e-9 times step of a multiple, so that 0.3 is a multiple of 0.1 despite
+// rounding. MultipleOf panics if step is zero.
+//
+//	MultipleOf(10, 6) // `Invalid must be a multiple of 6: 10`
+func MultipleOf[T Number](v, step T) error {
+	if step == 0 {
+		panic("validate: MultipleOf with a zero step")
+	}
+	if isMultiple(v, step) {
+		return nil
+	}
+	return Invalid(fmt.Sprintf("must be a multiple of %v", step), v)
+}
+
+func isMultiple[T Number](v, step T) bool {
+	if T(1)/T(2) == 0 {
+		return v/step*step == v
+	}
+	s := math.Abs(float64(step))
+	r := math.Abs(math.Mod(float64(v), s))
+	return r <= multipleTolerance*s || s-r <= multipleTolerance*s
+}
+
+// Require that the decimal form of the integer v takes at most width
+// characters, as for a fixed-width record field. The minus sign of a
+// negative number counts toward the width. Leading zeros are not counted,
+// since v has none; padding v to the width is left to the formatter.
+// FitsWidth panics if v is not an integer.
+//
+//	FitsWidth(-1234, 4) // `Invalid exceeds width 4: -1234`
+func FitsWidth(v interface{}, width int) error {
+	var s string
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(val.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s = strconv.FormatUint(val.Uint(), 10)
+	default:
+		panic(fmt.Sprintf("validate: FitsWidth of non-integer type %T", v))
+	}
+	if len(s) > width {
+		return Invalid(fmt.Sprintf("exceeds width %d", width), v)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRangeEx(t *testing.T) {
+	for _, test := range []struct {
+		v      int
+		lo, hi bool
+		msg    string
+	}{
+		{0, true, true, ""},
+		{100, true, true, ""},
+		{0, false, true, "Invalid out of range (0, 100]: 0"},
+		{100, false, true, ""},
+		{0, true, false, ""},
+		{100, true, false, "Invalid out of range [0, 100): 100"},
+		{0, false, false, "Invalid out of range (0, 100): 0"},
+		{100, false, false, "Invalid out of range (0, 100): 100"},
+		{50, false, false, ""},
+		{-1, true, true, "Invalid out of range [0, 100]: -1"},
+	} {
+		err := RangeEx(test.v, 0, 100, test.lo, test.hi)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error %v", test, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%+v: expected %q got %v", test, test.msg, err)
+		}
+	}
+	if err := RangeEx(math.NaN(), 0, 1, true, true); err == nil {
+		t.Errorf("expected NaN to be out of range")
+	}
+	if err := RangeEx("m", "a", "z", true, true); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestMonotonic(t *testing.T) {
+	for _, test := range []struct {
+		old, new int
+		strict   bool
+		msg      string
+	}{
+		{1, 2, true, ""},
+		{1, 2, false, ""},
+		{2, 2, true, "Version: must increase"},
+		{2, 2, false, ""},
+		{3, 2, true, "Version: must increase"},
+		{3, 2, false, "Version: must not decrease"},
+	} {
+		err := Monotonic("Version", test.old, test.new, test.strict)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%+v: unexpected error %v", test, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%+v: expected %q got %v", test, test.msg, err)
+		}
+	}
+}
+
+func TestMultipleOf(t *testing.T) {
+	for _, test := range []struct {
+		err error
+		msg string
+	}{
+		{MultipleOf(12, 6), ""},
+		{MultipleOf(0, 6), ""},
+		{MultipleOf(-18, 6), ""},
+		{MultipleOf(10, 6), "Invalid must be a multiple of 6: 10"},
+		{MultipleOf(uint8(250), 5), ""},
+		{MultipleOf(0.3, 0.1), ""},
+		{MultipleOf(-0.3, 0.1), ""},
+		{MultipleOf(1.000000000005, 0.01), ""},
+		{MultipleOf(1.00000000005, 0.01), "Invalid must be a multiple of 0.01: 1.00000000005"},
+		{MultipleOf(0.25, 0.1), "Invalid must be a multiple of 0.1: 0.25"},
+	} {
+		if test.msg == "" {
+			if test.err != nil {
+				t.Errorf("unexpected error %v", test.err)
+			}
+			continue
+		}
+		if test.err == nil || test.err.Error() != test.msg {
+			t.Errorf("expected %q got %v", test.msg, test.err)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for zero step")
+		}
+	}()
+	MultipleOf(1, 0)
+}
+
+func TestFitsWidth(t *testing.T) {
+	for _, test := range []struct {
+		err error
+		msg string
+	}{
+		{FitsWidth(999, 4), ""},
+		{FitsWidth(9999, 4), ""},
+		{FitsWidth(10000, 4), "Invalid exceeds width 4: 10000"},
+		{FitsWidth(-999, 4), ""},
+		{FitsWidth(-1234, 4), "Invalid exceeds width 4: -1234"},
+		{FitsWidth(0, 1), ""},
+		{FitsWidth(uint64(18446744073709551615), 20), ""},
+		{FitsWidth(int8(-128), 3), "Invalid exceeds width 3: -128"},
+	} {
+		if test.msg == "" {
+			if test.err != nil {
+				t.Errorf("unexpected error %v", test.err)
+			}
+			continue
+		}
+		if test.err == nil || test.err.Error() != test.msg {
+			t.Errorf("expected %q got %v", test.msg, test.err)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for non-integer")
+		}
+	}()
+	FitsWidth(1.5, 4)
+}
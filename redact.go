@@ -0,0 +1,55 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+)
+
+// Validate a sensitive property without revealing its value. Every leaf
+// error is replaced by a fixed message, so nothing derived from the value
+// appears in the result; the paths, leaf codes, hints (see Hinted) and
+// warnings (see Warn) are kept.
+//
+//	Redact("Password", pw) // `Password: invalid (redacted)`
+func Redact(property string, value interface{}) error {
+	return mapLeaves(Property(property, value), func(path string, leaf error) error {
+		return rewrapLeaf(leaf, redactedError{codeOf(leaf)})
+	})
+}
+
+type redactedError struct {
+	code string
+}
+
+func (err redactedError) Error() string { return "invalid (redacted)" }
+func (err redactedError) Code() string  { return err.code }
+
+// Rebuild err so that no leaf echoes an input value. Leaves wrapping an
+// InvalidError are replaced by its SafeMessage(), dropping the value; other
+// leaves are kept as they are. Paths, codes, hints (see Hinted) and
+// warnings (see Warn) are always kept, so Safe does not change severity. Use Safe before rendering errors on untrusted surfaces.
+//
+//	Safe(err) // `Email: Invalid email`
+func Safe(err error) error {
+	return mapLeaves(err, func(path string, leaf error) error {
+		var inv InvalidError
+		if !errors.As(leaf, &inv) {
+			return leaf
+		}
+		return rewrapLeaf(leaf, recodedError{errors.New(inv.SafeMessage()), codeOf(leaf)})
+	})
+}
+
+// The replacement of leaf, with the hint and warning marker of leaf.
+func rewrapLeaf(leaf, replacement error) error {
+	if hint := hintOf(leaf); hint != "" {
+		replacement = hintedError{replacement, hint}
+	}
+	if IsWarning(leaf) {
+		replacement = warning{replacement}
+	}
+	return replacement
+}
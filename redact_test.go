@@ -0,0 +1,97 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testSecret string
+
+func (s testSecret) Validate() error {
+	if len(s) < 12 {
+		return Errors{
+			Invalid("short password", string(s)),
+			PropertyFunc("Prefix", func() error { return Invalid(string(s[:3])) }),
+		}
+	}
+	return nil
+}
+
+// A secret whose failures are advisory and carry a hint.
+type testWeakSecret string
+
+func (s testWeakSecret) Validate() error {
+	return Hinted("Strength", As(string(s), func(v interface{}) error {
+		return Warn(Invalid("weak", v))
+	}), "add symbols")
+}
+
+func TestRedactSafeMarkers(t *testing.T) {
+	for name, err := range map[string]error{
+		"Redact": Redact("Password", testWeakSecret("hunter2")),
+		"Safe":   Safe(Property("Password", testWeakSecret("hunter2"))),
+	} {
+		if s := MaxSeverity(err); s != SeverityWarning {
+			t.Errorf("%s: expected severity warning got %v", name, s)
+		}
+		eachLeaf(err, func(path string, leaf error) {
+			if hintOf(leaf) != "add symbols" || strings.Contains(leaf.Error(), "hun") {
+				t.Errorf("%s: unexpected leaf %s: %#v", name, path, leaf)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	secret := "hunter2"
+	err := Redact("Password", testSecret(secret))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	msg := err.Error()
+	if msg != "Password: invalid (redacted); Password.Prefix: invalid (redacted)" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	for _, s := range []string{msg, fmt.Sprintf("%#v", err), fmt.Sprintf("%+v", err)} {
+		if strings.Contains(s, "hun") {
+			t.Errorf("value leaked into %q", s)
+		}
+	}
+	if err := Redact("Password", testSecret("correct horse battery")); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestSafe(t *testing.T) {
+	err := Errors{
+		PropertyFunc("Port", func() error { return IntString("80abc", 1, 65535) }),
+		PropertyFunc("Ratio", func() error { return FloatString("7.25", 0, 1) }),
+		PropertyFunc("Email", func() error { return Invalid("email", "bob@example") }),
+		Property("Password", testSecret("hunter2")),
+		NewPropertyError("Plain", Invalid()),
+	}
+	msg := Safe(err).Error()
+	expect := "Port: Invalid not a number; Ratio: Invalid out of range [0, 1]; " +
+		"Email: Invalid email; Password: Invalid short password; " +
+		"Password.Prefix: Invalid; Plain: Invalid"
+	if msg != expect {
+		t.Errorf("expected %q got %q", expect, msg)
+	}
+	for _, input := range []string{"80abc", "7.25", "bob@", "hun"} {
+		if strings.Contains(msg, input) {
+			t.Errorf("input %q leaked into %q", input, msg)
+		}
+	}
+	p, e := MarshalAPIErrors(Safe(Hinted("Password", testSecret("hunter2"), "use 12 characters")))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !strings.Contains(string(p), `"hint":"use 12 characters"`) || strings.Contains(string(p), "hun") {
+		t.Errorf("unexpected response %s", p)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Postal code formats by ISO 3166-1 alpha-2 region code.
+var postalCodes = map[string]*regexp.Regexp{
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"CA": regexp.MustCompile(`^(?i)[A-Z]\d[A-Z] ?\d[A-Z]\d$`),
+	"GB": regexp.MustCompile(`^(?i)[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+}
+
+// Phone number formats by region, national or with the country code,
+// matched after spaces, dashes, dots and parentheses are removed.
+var phoneNumbers = map[string]*regexp.Regexp{
+	"US": regexp.MustCompile(`^(\+?1)?[2-9]\d{2}[2-9]\d{6}$`),
+	"CA": regexp.MustCompile(`^(\+?1)?[2-9]\d{2}[2-9]\d{6}$`),
+	"GB": regexp.MustCompile(`^(\+44|0)\d{9,10}$`),
+	"DE": regexp.MustCompile(`^(\+49|0)\d{6,13}$`),
+	"FR": regexp.MustCompile(`^(\+33|0)[1-9]\d{8}$`),
+}
+
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// Require that s is a postal code of region, an ISO 3166-1 alpha-2 code.
+// The supported regions are US (ZIP and ZIP+4), CA, GB, DE and FR; any
+// other region is reported as unsupported with an error that is not a
+// validation error (see IsValidationError).
+//
+//	PostalCode("9021", "US") // `Invalid US postal code: "9021"`
+func PostalCode(s, region string) error {
+	return regionFormat(postalCodes, "postal code", s, s, region)
+}
+
+// Require that s is a phone number of region (see PostalCode for the
+// supported regions), written nationally or with the country code, as in
+// "(415) 555-2671" or "+1 415 555 2671". Only the format is checked.
+//
+//	Phone("555-2671", "US") // `Invalid US phone number: "555-2671"`
+func Phone(s, region string) error {
+	return regionFormat(phoneNumbers, "phone number", phoneSeparators.Replace(s), s, region)
+}
+
+func regionFormat(formats map[string]*regexp.Regexp, name, s, orig, region string) error {
+	region = strings.ToUpper(region)
+	re, ok := formats[region]
+	if !ok {
+		return internalError{fmt.Errorf("validate: unsupported %s region %q", name, region)}
+	}
+	if !re.MatchString(s) {
+		return Invalid(region+" "+name, orig)
+	}
+	return nil
+}
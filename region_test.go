@@ -0,0 +1,50 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+)
+
+func TestRegion(t *testing.T) {
+	for _, test := range []struct {
+		fn        func(s, region string) error
+		s, region string
+		msg       string
+	}{
+		{PostalCode, "90210", "US", ""},
+		{PostalCode, "90210-1234", "us", ""},
+		{PostalCode, "9021", "US", `Invalid US postal code: "9021"`},
+		{PostalCode, "SW1A 1AA", "GB", ""},
+		{PostalCode, "K1A 0B1", "CA", ""},
+		{PostalCode, "10115", "DE", ""},
+		{PostalCode, "1011", "DE", `Invalid DE postal code: "1011"`},
+		{PostalCode, "1234", "ZZ", `validate: unsupported postal code region "ZZ"`},
+		{Phone, "(415) 555-2671", "US", ""},
+		{Phone, "+1 415 555 2671", "US", ""},
+		{Phone, "555-2671", "US", `Invalid US phone number: "555-2671"`},
+		{Phone, "+44 20 7946 0958", "GB", ""},
+		{Phone, "01 23 45 67 89", "FR", ""},
+		{Phone, "123", "ZZ", `validate: unsupported phone number region "ZZ"`},
+	} {
+		err := test.fn(test.s, test.region)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%q %s: unexpected error %v", test.s, test.region, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%q %s: expected %q got %v", test.s, test.region, test.msg, err)
+		}
+	}
+	if err := PostalCode("1234", "ZZ"); IsValidationError(err) {
+		t.Errorf("unsupported region reported as a validation error")
+	}
+	err := PropertyFunc("Zip", func() error { return PostalCode("1234", "ZZ") })
+	if err == nil || IsValidationError(err) {
+		t.Errorf("unsupported region of a property reported as a validation error")
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Validation rules defined at runtime, such as rules loaded from a
+// database. Rules returns the rules for the field at path, like
+// "Address.City", or nil if there are none.
+type Schema interface {
+	Rules(path string) []func(interface{}) error
+}
+
+// Validate the exported fields of the struct (or struct pointer) v against
+// schema. Fields holding structs, or non-nil pointers to structs, are
+// descended into after their own rules run; slices and maps are not. All
+// failures are collected into Errors, attributed to the field paths. An
+// embedded struct is a field like any other, at a path named after its
+// type; when an embedded struct pointer is nil its promoted fields do not
+// exist, so only the rules of the embedded field itself run. A pointer
+// back to a struct that is already being descended into, as in a cyclic
+// list, is not followed again. The schema is consulted on every call, so
+// rule changes take effect without restarting. Validate() methods are not
+// called (see V).
+func VSchema(v interface{}, schema Schema) error {
+	return vSchema("VSchema", v, schema, false)
+}
+
+// Like VSchema but fields are read through their getters when present, as
+// on protobuf-generated messages, so that rules see the value of an unset
+// optional field rather than a nil pointer. The getter of field Foo is a
+// method GetFoo taking no arguments and returning one value. A GetFoo that
+// an embedded field's type also has is taken to be promoted from it, so it
+// belongs to that type, not to the field, and is not called; this includes
+// a GetFoo of the struct's own that shadows one of an embedded type.
+// Fields without a getter are read directly. Getters with pointer
+// receivers are only found when v is a pointer.
+//
+//	VSchemaGetters(msg, schema) // rules for "Name" get msg.GetName()
+func VSchemaGetters(v interface{}, schema Schema) error {
+	return vSchema("VSchemaGetters", v, schema, true)
+}
+
+func vSchema(name string, v interface{}, schema Schema, getters bool) error {
+	w := &schemaWalk{schema: schema, getters: getters, visiting: make(map[visit]bool)}
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr {
+		if !val.IsNil() {
+			w.visiting[visit{val.Pointer(), val.Type()}] = true
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: %s of non-struct type %T", name, v))
+	}
+	w.fields(val, nil)
+	return w.errs.Err()
+}
+
+// Validate v against each schema in turn (see VSchema), returning one
+// result per schema in the same order, such as the old and new schemas of
+// a migration. A nil result means v satisfies that schema.
+//
+//	results := VAgainst(user, oldSchema, newSchema)
+func VAgainst(v interface{}, schemas ...Schema) []error {
+	results := make([]error, len(schemas))
+	for i, schema := range schemas {
+		results[i] = VSchema(v, schema)
+	}
+	return results
+}
+
+// The state of a VSchema call descending through nested structs.
+type schemaWalk struct {
+	schema   Schema
+	getters  bool
+	visiting map[visit]bool
+	errs     Errors
+}
+
+// A pointer being descended into, identified with its type since a struct
+// and its first field share an address.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// Check the fields of the struct val, found at the property names, and
+// descend into those holding structs.
+func (w *schemaWalk) fields(val reflect.Value, names []string) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		names := append(names[:len(names):len(names)], field.Name)
+		fval := val.Field(i)
+		if w.getters {
+			fval = getterValue(val, field.Name, fval)
+		}
+		for _, rule := range w.schema.Rules(strings.Join(names, ".")) {
+			if err := rule(fval.Interface()); err != nil {
+				w.errs = append(w.errs, nestedError(names, err))
+			}
+		}
+		if fval.Kind() == reflect.Ptr && !fval.IsNil() {
+			key := visit{fval.Pointer(), fval.Type()}
+			if w.visiting[key] || fval.Elem().Kind() != reflect.Struct {
+				continue
+			}
+			w.visiting[key] = true
+			w.fields(fval.Elem(), names)
+			delete(w.visiting, key)
+			continue
+		}
+		if fval.Kind() == reflect.Struct {
+			w.fields(fval, names)
+		}
+	}
+}
+
+// The error err of the property at a path of names, nested one
+// PropertyError per name like results of Property in nested Validate
+// methods.
+func nestedError(names []string, err error) error {
+	for i := len(names) - 1; i >= 0; i-- {
+		err = PropertyError{property: names[i], err: err}
+	}
+	return err
+}
+
+// The result of the getter for the field name of the struct val, or fval,
+// the field itself, if val has no such getter of its own.
+func getterValue(val reflect.Value, name string, fval reflect.Value) reflect.Value {
+	if promotesMethod(val.Type(), "Get"+name) {
+		return fval
+	}
+	if val.CanAddr() {
+		val = val.Addr()
+	}
+	method := val.MethodByName("Get" + name)
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return fval
+	}
+	return method.Call(nil)[0]
+}
+
+// True if the type of an embedded field of the struct type typ, or a
+// pointer to it, has the method name, which typ then has by promotion.
+func promotesMethod(typ reflect.Type, name string) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		t := field.Type
+		if _, ok := t.MethodByName(name); ok {
+			return true
+		}
+		if t.Kind() != reflect.Ptr {
+			if _, ok := reflect.PointerTo(t).MethodByName(name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
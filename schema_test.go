@@ -0,0 +1,162 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testSchema map[string][]func(interface{}) error
+
+func (s testSchema) Rules(path string) []func(interface{}) error { return s[path] }
+
+type testAddress struct {
+	City string
+	Zip  string
+}
+
+type testUser struct {
+	Name    string
+	Address *testAddress
+}
+
+func testNonEmpty(v interface{}) error {
+	if v.(string) == "" {
+		return errors.New("required")
+	}
+	return nil
+}
+
+func TestVSchema(t *testing.T) {
+	schema := testSchema{
+		"Name":         {testNonEmpty},
+		"Address.City": {testNonEmpty},
+	}
+	user := testUser{"", &testAddress{}}
+	err := VSchema(&user, schema)
+	expect := []string{"Address.City", "Name"}
+	if fields := InvalidFields(err); !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expected %q got %q", expect, fields)
+	}
+	if err.Error() != "Name: required; Address.City: required" {
+		t.Errorf("unexpected message %q", err)
+	}
+	user = testUser{"a", nil}
+	if err := VSchema(user, schema); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// A message shaped like protobuf-generated code.
+type testProto struct {
+	Name    *string
+	Address *testAddress
+	Nick    *string
+}
+
+func (m *testProto) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *testProto) GetAddress() *testAddress {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func TestVSchemaGetters(t *testing.T) {
+	nick := func(v interface{}) error {
+		if v.(*string) != nil {
+			return errors.New("set")
+		}
+		return nil
+	}
+	schema := testSchema{
+		"Name":         {testNonEmpty},
+		"Address.City": {testNonEmpty},
+		"Nick":         {nick},
+	}
+	err := VSchemaGetters(&testProto{}, schema)
+	if err == nil || err.Error() != "Name: required" {
+		t.Errorf("unexpected error %v", err)
+	}
+	name, s := "a", "s"
+	err = VSchemaGetters(&testProto{Name: &name, Address: &testAddress{}, Nick: &s}, schema)
+	if err == nil || err.Error() != "Address.City: required; Nick: set" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+type TestEmbedded struct {
+	ID string
+}
+
+// Getters promoted to testEmbedding, not belonging to its fields.
+func (e *TestEmbedded) GetName() string { return e.ID }
+func (e *TestEmbedded) GetNick() string { return e.ID }
+
+type testEmbedding struct {
+	*TestEmbedded
+	Name string
+	Nick string
+	Zone string
+}
+
+func (e *testEmbedding) GetZone() string { return strings.ToUpper(e.Zone) }
+
+func TestVSchemaNilEmbedded(t *testing.T) {
+	schema := testSchema{
+		"TestEmbedded.ID": {testNonEmpty},
+		"Name":            {testNonEmpty},
+		"Nick":            {testNonEmpty},
+		"Zone":            {testUpper},
+	}
+	if err := VSchema(&testEmbedding{Name: "a", Nick: "b", Zone: "Z"}, schema); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := VSchema(&testEmbedding{&TestEmbedded{}, "a", "b", "Z"}, schema)
+	if err == nil || err.Error() != "TestEmbedded.ID: required" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := VSchemaGetters(&testEmbedding{Name: "a", Nick: "b", Zone: "z"}, schema); err != nil {
+		t.Errorf("getters: unexpected error %v", err)
+	}
+	err = VSchemaGetters(&testEmbedding{&TestEmbedded{}, "a", "b", "z"}, schema)
+	if err == nil || err.Error() != "TestEmbedded.ID: required" {
+		t.Errorf("getters: unexpected error %v", err)
+	}
+}
+
+func testUpper(v interface{}) error {
+	if s := v.(string); s != strings.ToUpper(s) {
+		return errors.New("not upper case")
+	}
+	return nil
+}
+
+func TestVAgainst(t *testing.T) {
+	old := testSchema{"Name": {testNonEmpty}}
+	next := testSchema{"Name": {testNonEmpty}, "Address.City": {testNonEmpty}}
+	results := VAgainst(testUser{"a", &testAddress{}}, old, next)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results got %d", len(results))
+	}
+	if results[0] != nil {
+		t.Errorf("old schema: unexpected error %v", results[0])
+	}
+	if results[1] == nil || results[1].Error() != "Address.City: required" {
+		t.Errorf("new schema: unexpected error %v", results[1])
+	}
+	if results := VAgainst(testUser{"a", nil}); len(results) != 0 {
+		t.Errorf("unexpected results %v", results)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Mark err as advisory. The warning renders as err does, and unwraps to it.
+// Warn returns nil if err is nil.
+//
+//	PropertyFunc("Nickname", func() error { return Warn(checkNickname(u.Nickname)) })
+func Warn(err error) error {
+	if err == nil {
+		return nil
+	}
+	return warning{err}
+}
+
+type warning struct{ err error }
+
+func (w warning) Error() string { return w.err.Error() }
+func (w warning) Unwrap() error { return w.err }
+func (w warning) Code() string  { return codeOf(w.err) }
+
+// True if the leaf error err was marked advisory with Warn.
+func IsWarning(err error) bool {
+	var w warning
+	return errors.As(err, &w)
+}
+
+// How serious a failure is. Severities are ordered: SeverityNone for no
+// failure, below SeverityWarning for an advisory leaf (see Warn), below
+// SeverityError for any other failure.
+type Severity int
+
+const (
+	SeverityNone Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityNone:
+		return "none"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// The highest severity among the leaves of err, for a gate that proceeds
+// when every failure is a warning and blocks on any error. A nil err has
+// SeverityNone.
+//
+//	if validate.MaxSeverity(err) == validate.SeverityError {
+//		return err
+//	}
+func MaxSeverity(err error) Severity {
+	sev := SeverityNone
+	eachLeaf(err, func(path string, leaf error) {
+		if IsWarning(leaf) {
+			sev = max(sev, SeverityWarning)
+		} else {
+			sev = SeverityError
+		}
+	})
+	return sev
+}
+
+// Rebuild err with the severity of each leaf decided by policy, which is
+// given the leaf's path and whether it is a warning and returns whether it
+// should be one. Warnings that policy rejects become ordinary failures and
+// other leaves it accepts are marked with Warn, so an application can
+// promote or demote failures per field and environment. A policy returning
+// warn unchanged leaves err as it is.
+//
+//	err = Escalate(err, func(path string, warn bool) bool {
+//		return warn && !(production && path == "Email")
+//	})
+func Escalate(err error, policy func(path string, warn bool) bool) error {
+	return mapLeaves(err, func(path string, leaf error) error {
+		var w warning
+		warn := errors.As(leaf, &w)
+		switch {
+		case warn && !policy(path, warn):
+			return w.err
+		case !warn && policy(path, warn):
+			return Warn(leaf)
+		}
+		return leaf
+	})
+}
+
+// Check *ptr with validate, replacing it with def if it is invalid. The
+// replacement is reported as a warning (see Warn) wrapping the original
+// failure, so CheckSeverity and IsWarning treat it as advisory. A valid
+// *ptr is left untouched and nil is returned.
+//
+//	OrDefault(&cfg.Workers, positive, 4) // `must be positive (using default 4)`
+func OrDefault[T any](ptr *T, validate func(T) error, def T) error {
+	err := validate(*ptr)
+	if err == nil || err == Skipped {
+		return nil
+	}
+	*ptr = def
+	return Warn(fmt.Errorf("%w (using default %v)", err, def))
+}
+
+// Validate v (see V) and split the failures by severity. Leaves marked with
+// Warn go to warns and all others to errs, each keeping its path. Either is
+// nil when it has no leaves.
+//
+//	errs, warns := CheckSeverity(user)
+//	if errs != nil {
+//		return errs
+//	}
+//	log.Print(warns)
+func CheckSeverity(v interface{}) (errs, warns error) {
+	err := V(v)
+	errs = mapLeaves(err, func(path string, leaf error) error {
+		if IsWarning(leaf) {
+			return nil
+		}
+		return leaf
+	})
+	warns = mapLeaves(err, func(path string, leaf error) error {
+		if IsWarning(leaf) {
+			return leaf
+		}
+		return nil
+	})
+	return errs, warns
+}
@@ -0,0 +1,126 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+type testSignup struct {
+	Email    string
+	Nickname string
+}
+
+func (s testSignup) Validate() error {
+	return Errors{
+		PropertyFunc("Email", func() error {
+			if s.Email == "" {
+				return errors.New("required")
+			}
+			return nil
+		}),
+		PropertyFunc("Nickname", func() error {
+			if len(s.Nickname) > 8 {
+				return Warn(errors.New("will be truncated"))
+			}
+			return nil
+		}),
+	}.Err()
+}
+
+func TestCheckSeverity(t *testing.T) {
+	form := As(testSignup{Nickname: "bartholomew"}, func(v interface{}) error {
+		return Property("Signup", v)
+	})
+	errs, warns := CheckSeverity(form)
+	if errs == nil || errs.Error() != "Signup.Email: required" {
+		t.Errorf("errs: unexpected %v", errs)
+	}
+	if warns == nil || warns.Error() != "Signup.Nickname: will be truncated" {
+		t.Errorf("warns: unexpected %v", warns)
+	}
+	if IsWarning(errors.New("x")) || !IsWarning(Warn(errors.New("x"))) {
+		t.Errorf("IsWarning does not match Warn")
+	}
+
+	errs, warns = CheckSeverity(testSignup{Email: "a@example.com"})
+	if errs != nil || warns != nil {
+		t.Errorf("unexpected errs=%v warns=%v", errs, warns)
+	}
+}
+
+func TestOrDefault(t *testing.T) {
+	positive := func(n int) error {
+		if n <= 0 {
+			return errors.New("must be positive")
+		}
+		return nil
+	}
+	workers := 8
+	if err := OrDefault(&workers, positive, 4); err != nil || workers != 8 {
+		t.Errorf("unexpected result %d (%v)", workers, err)
+	}
+	workers = -1
+	err := PropertyFunc("Workers", func() error { return OrDefault(&workers, positive, 4) })
+	if workers != 4 {
+		t.Errorf("expected default 4 got %d", workers)
+	}
+	if err == nil || err.Error() != "Workers: must be positive (using default 4)" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if errs, warns := CheckSeverity(As(err, func(v interface{}) error { return v.(error) })); errs != nil || warns == nil {
+		t.Errorf("expected only a warning got errs=%v warns=%v", errs, warns)
+	}
+}
+
+func TestEscalate(t *testing.T) {
+	err := Errors{
+		NewPropertyError("Email", Warn(errors.New("unverified domain"))),
+		NewPropertyError("Nickname", Warn(errors.New("will be truncated"))),
+		NewPropertyError("Age", errors.New("required")),
+	}
+	production := func(path string, warn bool) bool { return warn && path != "Email" }
+	errs, warns := CheckSeverity(As(Escalate(err, production), func(v interface{}) error { return v.(error) }))
+	if errs == nil || errs.Error() != "Email: unverified domain; Age: required" {
+		t.Errorf("errs: unexpected %v", errs)
+	}
+	if warns == nil || warns.Error() != "Nickname: will be truncated" {
+		t.Errorf("warns: unexpected %v", warns)
+	}
+
+	coded := NewPropertyError("Email", testCodedError{"email", "bad domain"})
+	demoted := Escalate(coded, func(string, bool) bool { return true })
+	eachLeaf(demoted, func(_ string, leaf error) {
+		if !IsWarning(leaf) || codeOf(leaf) != "email" {
+			t.Errorf("unexpected demoted leaf %#v", leaf)
+		}
+	})
+
+	lenient := func(path string, warn bool) bool { return warn || path == "Age" }
+	_, warns = CheckSeverity(As(Escalate(err, lenient), func(v interface{}) error { return v.(error) }))
+	if warns == nil || len(Errors{warns}.Flatten()) != 3 {
+		t.Errorf("warns: unexpected %v", warns)
+	}
+}
+
+func TestMaxSeverity(t *testing.T) {
+	for _, test := range []struct {
+		v   interface{}
+		sev Severity
+	}{
+		{testSignup{Nickname: "bartholomew"}, SeverityError},
+		{testSignup{Email: "a@example.com", Nickname: "bartholomew"}, SeverityWarning},
+		{testSignup{Email: "a@example.com"}, SeverityNone},
+	} {
+		if sev := MaxSeverity(V(test.v)); sev != test.sev {
+			t.Errorf("%+v: expected %v got %v", test.v, test.sev, sev)
+		}
+	}
+	mixed := Errors{Warn(errors.New("a")), Property("Bars", testBars{testBar{0}})}
+	if sev := MaxSeverity(Escalate(mixed, func(string, bool) bool { return true })); sev != SeverityWarning {
+		t.Errorf("expected warning got %v", sev)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A chain of rules checking a string. Each method returns the chain, and
+// Err returns the first rule that failed; once a rule fails the rest are
+// not evaluated.
+//
+//	return validate.PropertyFunc("Name", func() error {
+//		return validate.String(u.Name).NonEmpty().MaxLen(100).Err()
+//	})
+type StringRule struct {
+	s   string
+	err error
+}
+
+// Begin a chain of rules checking s.
+func String(s string) *StringRule {
+	return &StringRule{s: s}
+}
+
+// Require that the string is not empty, failing with ErrRequired.
+func (r *StringRule) NonEmpty() *StringRule {
+	if r.err == nil && r.s == "" {
+		r.err = ErrRequired
+	}
+	return r
+}
+
+// Require that the string has at least n characters (runes).
+//
+//	String("ab").MinLen(3) // `must be at least 3 characters (has 2)`
+func (r *StringRule) MinLen(n int) *StringRule {
+	if size := utf8.RuneCountInString(r.s); r.err == nil && size < n {
+		r.err = fmt.Errorf("must be at least %d characters (has %d)", n, size)
+	}
+	return r
+}
+
+// Require that the string has at most n characters (runes).
+//
+//	String("abc").MaxLen(2) // `must be at most 2 characters (has 3)`
+func (r *StringRule) MaxLen(n int) *StringRule {
+	if size := utf8.RuneCountInString(r.s); r.err == nil && size > n {
+		r.err = fmt.Errorf("must be at most %d characters (has %d)", n, size)
+	}
+	return r
+}
+
+// Require that the string matches re.
+//
+//	String("x1").Matches(regexp.MustCompile(`^[a-z]+$`)) // `Invalid does not match ^[a-z]+$: "x1"`
+func (r *StringRule) Matches(re *regexp.Regexp) *StringRule {
+	if r.err == nil && !re.MatchString(r.s) {
+		r.err = Invalid("does not match "+re.String(), r.s)
+	}
+	return r
+}
+
+// The first failed rule, or nil if every rule passed.
+func (r *StringRule) Err() error {
+	return r.err
+}
+
+// Require that s is accepted by at least one of validators, such as a field
+// taking either an IP address or a CIDR prefix. Otherwise the error names
+// every format s failed, in order. Formats reported by an InvalidError are
+// named by what the value is not, as given to Invalid, or else by the
+// reason it is invalid; any other failure, such as one without a name, is
+// described as "other format".
+//
+//	AnyFormat("x", IP, CIDR) // `Invalid IP address or CIDR prefix: "x"`
+func AnyFormat(s string, validators ...func(string) error) error {
+	formats := make([]string, 0, len(validators))
+	other := false
+	for _, validate := range validators {
+		err := validate(s)
+		if err == nil {
+			return nil
+		}
+		var inv InvalidError
+		switch {
+		case !errors.As(err, &inv):
+			other = true
+		case inv.what != "":
+			formats = append(formats, inv.what)
+		case inv.reason != "":
+			formats = append(formats, inv.reason)
+		default:
+			other = true
+		}
+	}
+	if other {
+		formats = append(formats, "other format")
+	}
+	if len(formats) == 0 {
+		return Invalid(s)
+	}
+	return Invalid(strings.Join(formats, " or "), s)
+}
+
+// Require that s is safe to echo into a log line or a header: valid UTF-8,
+// at most maxLen bytes, and free of control characters. The rejected code
+// points are those of unicode.IsControl, U+0000 to U+001F and U+007F to
+// U+009F, which include NUL, tab, CR and LF, and the line and paragraph
+// separators U+2028 and U+2029. The value is quoted in the message, so the
+// message itself is safe to log.
+//
+//	SafeString("a\nb", 64) // `Invalid control character U+000A: "a\nb"`
+func SafeString(s string, maxLen int) error {
+	if len(s) > maxLen {
+		return InvalidError{what: fmt.Sprintf("too long (at most %d bytes, has %d)", maxLen, len(s))}
+	}
+	if !utf8.ValidString(s) {
+		return Invalid("UTF-8", s)
+	}
+	for _, r := range s {
+		if unicode.IsControl(r) || r == '\u2028' || r == '\u2029' {
+			return Invalid(fmt.Sprintf("control character %U", r), s)
+		}
+	}
+	return nil
+}
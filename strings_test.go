@@ -0,0 +1,93 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestStringRule(t *testing.T) {
+	lower := regexp.MustCompile(`^[a-z]+$`)
+	for _, test := range []struct {
+		s   string
+		msg string
+	}{
+		{"abc", ""},
+		{"", "required"},
+		{"abcdef", "must be at most 5 characters (has 6)"},
+		{"ABCDEF", "must be at most 5 characters (has 6)"},
+		{"x1", `Invalid does not match ^[a-z]+$: "x1"`},
+		{"x", "must be at least 2 characters (has 1)"},
+		{"héllo", `Invalid does not match ^[a-z]+$: "héllo"`},
+	} {
+		err := String(test.s).NonEmpty().MaxLen(5).MinLen(2).Matches(lower).Err()
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", test.s, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%q: expected %q got %v", test.s, test.msg, err)
+		}
+	}
+}
+
+func TestAnyFormat(t *testing.T) {
+	slug := func(s string) error {
+		if !regexp.MustCompile(`^[a-z-]+$`).MatchString(s) {
+			return errors.New("not a slug")
+		}
+		return nil
+	}
+	token := func(string) error { return errExpired }
+	if err := AnyFormat("10.0.0.0/8", IP, CIDR); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := AnyFormat("x!", IP, CIDR)
+	if err == nil || err.Error() != `Invalid IP address or CIDR prefix: "x!"` {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = AnyFormat("X", IP, slug)
+	if err == nil || err.Error() != `Invalid IP address or other format: "X"` {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = AnyFormat("X", slug, token, func(string) error { return Invalid() }, CIDR)
+	if err == nil || err.Error() != `Invalid expired or CIDR prefix or other format: "X"` {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := AnyFormat("x"); err == nil {
+		t.Errorf("expected error without formats")
+	}
+}
+
+func TestSafeString(t *testing.T) {
+	for _, test := range []struct {
+		s   string
+		msg string
+	}{
+		{"Mozilla/5.0 (X)", ""},
+		{"héllo", ""},
+		{"a\nb", `Invalid control character U+000A: "a\nb"`},
+		{"a\r\nX-Admin: 1", `Invalid control character U+000D: "a\r\nX-Admin: 1"`},
+		{"nul\x00", `Invalid control character U+0000: "nul\x00"`},
+		{"line\u2028sep", "Invalid control character U+2028: \"line\\u2028sep\""},
+		{"\xff", `Invalid UTF-8: "\xff"`},
+		{"0123456789abcdef!", "Invalid too long (at most 16 bytes, has 17)"},
+	} {
+		err := SafeString(test.s, 16)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", test.s, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("%q: expected %q got %v", test.s, test.msg, err)
+		}
+	}
+}
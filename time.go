@@ -0,0 +1,48 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"time"
+)
+
+// Require that expiry has not passed at now. An expiry exactly at now has
+// not passed. Pass the current time in as now, so tests can fix the clock.
+//
+//	NotExpired(token.Expiry, now) // `Invalid: expired`
+func NotExpired(expiry, now time.Time) error {
+	if expiry.Before(now) {
+		return errExpired
+	}
+	return nil
+}
+
+var errExpired = InvalidError{reason: "expired"}
+
+// Like NotExpired using the real clock.
+func NotExpiredNow(expiry time.Time) error {
+	return NotExpired(expiry, time.Now())
+}
+
+// Require that s is a time in the given layout, in the form of time.Parse.
+// Only the form of s is checked, so a layout without a zone accepts times
+// that time.Parse would take as UTC, and a zone offset in s need not match
+// any particular location.
+//
+//	TimeString("2012-13-01", "2006-01-02") // `Invalid time: "2012-13-01"`
+func TimeString(s, layout string) error {
+	if _, err := time.Parse(layout, s); err != nil {
+		return Invalid("time", s)
+	}
+	return nil
+}
+
+// Require that s is an RFC 3339 timestamp, which must have an explicit zone
+// offset or Z.
+//
+//	RFC3339("2012-07-03 23:27") // `Invalid time: "2012-07-03 23:27"`
+func RFC3339(s string) error {
+	return TimeString(s, time.RFC3339)
+}
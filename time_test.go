@@ -0,0 +1,53 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotExpired(t *testing.T) {
+	now := time.Date(2012, 7, 3, 23, 27, 22, 0, time.UTC)
+	if err := NotExpired(now.Add(-time.Nanosecond), now); err == nil || err.Error() != "Invalid: expired" {
+		t.Errorf("past: unexpected error %v", err)
+	}
+	if err := NotExpired(now.Add(time.Hour), now); err != nil {
+		t.Errorf("future: unexpected error %v", err)
+	}
+	if err := NotExpired(now, now); err != nil {
+		t.Errorf("now: unexpected error %v", err)
+	}
+	if err := NotExpiredNow(time.Now().Add(time.Hour)); err != nil {
+		t.Errorf("real clock: unexpected error %v", err)
+	}
+	if msg := NotExpired(now.Add(-time.Hour), now).(InvalidError).SafeMessage(); msg != "Invalid: expired" {
+		t.Errorf("unexpected safe message %q", msg)
+	}
+}
+
+func TestTimeString(t *testing.T) {
+	for _, test := range []struct {
+		err error
+		msg string
+	}{
+		{RFC3339("2012-07-03T23:27:22Z"), ""},
+		{RFC3339("2012-07-03T23:27:22-07:00"), ""},
+		{RFC3339("2012-07-03T23:27:22"), `Invalid time: "2012-07-03T23:27:22"`},
+		{RFC3339("2012-07-03 23:27"), `Invalid time: "2012-07-03 23:27"`},
+		{TimeString("03/07/2012", "02/01/2006"), ""},
+		{TimeString("2012-13-01", "2006-01-02"), `Invalid time: "2012-13-01"`},
+	} {
+		if test.msg == "" {
+			if test.err != nil {
+				t.Errorf("unexpected error %v", test.err)
+			}
+			continue
+		}
+		if test.err == nil || test.err.Error() != test.msg {
+			t.Errorf("expected %q got %v", test.msg, test.err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+// A node in the tree of a validation error (see Tree). Segment is a property
+// name like "Bars" or an index like "[1]". A node is either internal, with
+// Children and a nil Leaf, or a leaf holding the originating error.
+type Node struct {
+	Segment  string
+	Children []*Node
+	Leaf     error
+}
+
+// The hierarchy of err, for rendering errors as a collapsible tree. The
+// root has an empty Segment. The members of an aggregate become siblings,
+// and errors below the same path share internal nodes, so the leaves of
+// Bars[1].Baz and Bars[2].Baz are both found under the one "Bars" node. A
+// leaf without a path below a node has an empty Segment. Tree returns nil
+// if err is nil.
+func Tree(err error) *Node {
+	if err == nil {
+		return nil
+	}
+	root := &Node{}
+	root.insert(err)
+	return root
+}
+
+func (n *Node) insert(err error) {
+	switch e := err.(type) {
+	case nil:
+	case PropertyError:
+		var segments []string
+		if e.property != "" {
+			segments = append(segments, e.property)
+		}
+		if e.index != nil {
+			segments = append(segments, formatIndex(e.index))
+		}
+		if len(segments) == 0 {
+			n.insert(e.err)
+			return
+		}
+		parent := n
+		for _, segment := range segments[:len(segments)-1] {
+			parent = parent.internal(segment)
+		}
+		last := segments[len(segments)-1]
+		switch e.err.(type) {
+		case PropertyError, multiError, contextError:
+			parent.internal(last).insert(e.err)
+		default:
+			parent.Children = append(parent.Children, &Node{Segment: last, Leaf: e.err})
+		}
+	case contextError:
+		n.insert(e.err)
+	case multiError:
+		for _, child := range e.Unwrap() {
+			n.insert(child)
+		}
+	default:
+		n.Children = append(n.Children, &Node{Leaf: err})
+	}
+}
+
+// The internal child of n for segment, added if n has none.
+func (n *Node) internal(segment string) *Node {
+	for _, child := range n.Children {
+		if child.Segment == segment && child.Leaf == nil {
+			return child
+		}
+	}
+	child := &Node{Segment: segment}
+	n.Children = append(n.Children, child)
+	return child
+}
@@ -0,0 +1,60 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// An indented outline of the tree below n.
+func testOutline(n *Node, depth int, b *strings.Builder) {
+	for _, child := range n.Children {
+		fmt.Fprintf(b, "%s%q", strings.Repeat("  ", depth), child.Segment)
+		if child.Leaf != nil {
+			fmt.Fprintf(b, " %v", child.Leaf)
+		}
+		b.WriteString("\n")
+		testOutline(child, depth+1, b)
+	}
+}
+
+func TestTree(t *testing.T) {
+	err := Errors{
+		Property("Bars", testBars{testBar{0}, testBar{1}, testBar{0}}),
+		NewPropertyError("Email", errors.New("required")),
+		errors.New("plain"),
+	}
+	var b strings.Builder
+	testOutline(Tree(err), 0, &b)
+	expect := `"Bars"
+  "[0]"
+    "Baz" qux
+  "[2]"
+    "Baz" qux
+"Email" required
+"" plain
+`
+	if b.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, b.String())
+	}
+
+	b.Reset()
+	testOutline(Tree(Errors{EachFrom("Bars", testBars{testBar{0}, testBar{0}}, 0)}), 0, &b)
+	expect = `"Bars"
+  "[0]"
+    "Baz" qux
+  "[1]"
+    "Baz" qux
+`
+	if b.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, b.String())
+	}
+	if Tree(nil) != nil {
+		t.Errorf("expected nil tree")
+	}
+}
@@ -50,6 +50,9 @@ package validate
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 )
 
 // The interface that validatable types should satisfy.
@@ -57,15 +60,124 @@ type Interface interface {
 	Validate() error
 }
 
-// Call Validate() on v if v is validatable.
+// Adapt a value whose type cannot be given a Validate() method.
+//		validator.Property("CreatedAt", validator.As(foo.CreatedAt, notZeroTime))
+func As(value interface{}, validate func(interface{}) error) Interface {
+	return adapter{value, validate}
+}
+
+type adapter struct {
+	value    interface{}
+	validate func(interface{}) error
+}
+
+func (a adapter) Validate() error { return a.validate(a.value) }
+
+// Carry the index of a collection element with it, so that the element can
+// be validated away from the loop over its collection, as by a worker
+// goroutine, and its errors still begin with the index.
+//		validator.V(validator.WithIndex(3, bar)) // `[3].Baz: qux`
+func WithIndex(index interface{}, v Interface) Interface {
+	return indexed{index, v}
+}
+
+type indexed struct {
+	index interface{}
+	value Interface
+}
+
+func (v indexed) Validate() error { return Index(v.index, v.value) }
+
+// Implemented by slice and map types to have V validate their elements
+// without reflection, as a reflection-free alternative to helpers like
+// EachFrom. V calls ValidateElements() only on values that do not also
+// implement Interface; a type implementing both should call it from its
+// Validate() method.
+//		type Bars []Bar
+//		func (bars Bars) ValidateElements() error {
+//			for i, bar := range bars {
+//				if err := validator.Index(i, bar); err != nil {
+//					return err
+//				}
+//			}
+//			return nil
+//		}
+type CollectionValidator interface {
+	ValidateElements() error
+}
+
+// Call Validate() on v if v is validatable, or ValidateElements() if v is a
+// CollectionValidator.
+//
+// A nil v is valid. So is a nil pointer, even when its type is validatable;
+// it is treated as absent and Validate() is not called on it.
 func V(v interface{}) error {
+	_, err := VDetailed(v)
+	return err
+}
+
+// Like V, but prefix a validation error's message with context, free text
+// describing what was being validated. The context is not part of any path;
+// functions walking leaves, like InvalidFields and PointerErrors, see the
+// paths of the validation error, which is also available unchanged through
+// errors.As or errors.Unwrap.
+//		validator.VIn("while loading user config", cfg) // `while loading user config: Email: required`
+func VIn(context string, v interface{}) error {
+	if err := V(v); err != nil {
+		return contextError{context, err}
+	}
+	return nil
+}
+
+type contextError struct {
+	context string
+	err     error
+}
+
+func (err contextError) Error() string { return err.context + ": " + err.err.Error() }
+func (err contextError) Unwrap() error { return err.err }
+
+// Validate v and return it, panicking with the validation error if it is
+// invalid. For package-level variables and tests, where an invalid value is
+// a programming error, not for validating input.
+//		var cfg = validator.MustValid(Config{Port: 80})
+func MustValid[T Interface](v T) T {
+	if err := V(v); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Like V, but also report whether v was validated at all. Values that are
+// not validatable, and nil values, are skipped with validated false.
+//
+// A Validate() method returning Skipped is also reported with validated
+// false and a nil error.
+func VDetailed(v interface{}) (validated bool, err error) {
+	switch v.(type) {
+	case Interface, CollectionValidator:
+		if val := reflect.ValueOf(v); val.Kind() == reflect.Ptr && val.IsNil() {
+			return false, nil
+		}
+	}
 	switch v.(type) {
 	case Interface:
-		return v.(Interface).Validate()
+		err = v.(Interface).Validate()
+	case CollectionValidator:
+		err = v.(CollectionValidator).ValidateElements()
+	default:
+		return false, nil
 	}
-	return nil
+	if err == Skipped {
+		return false, nil
+	}
+	return true, err
 }
 
+// Returned by a Validate() method or a PropertyFunc/IndexFunc closure to
+// signal that validation did not apply. It is never reported as an error.
+var Skipped = errors.New("validate: skipped")
+
 // Validate property values.
 //		type Qux int
 //		func (qux Qux) Validate() error { return errors.New("qux") }
@@ -78,60 +190,298 @@ func Property(property string, value interface{}) error {
 	return PropertyFunc(property, func() error { return V(value) })
 }
 
+// The error reported by PropertyRequired for missing values.
+var ErrRequired = errors.New("required")
+
+// Like Property, but a nil value or the zero value of its type is invalid.
+// Zero values are detected with reflect.Value.IsZero, so empty strings,
+// zero numbers, nil pointers, slices and maps, and structs whose fields are
+// all zero are missing. Empty but non-nil slices and maps are not.
+//		validator.PropertyRequired("Email", "") // `Email: required`
+func PropertyRequired(property string, value interface{}) error {
+	if value == nil || reflect.ValueOf(value).IsZero() {
+		return PropertyError{property: property, err: ErrRequired}
+	}
+	return Property(property, value)
+}
+
 // Used in tricker validation cases.
 // 
 // Try to use Property() instead.
 //
 func PropertyFunc(property interface{}, validate func() error) error {
-	if err := validate(); err != nil {
-		return PropertyError{fmt.Sprint(property), nil, err}
+	if err := validate(); err != nil && err != Skipped {
+		return PropertyError{property: fmt.Sprint(property), err: err}
+	}
+	return nil
+}
+
+// Validate a property, rendering errors with a human readable label in
+// place of the property name. The property name is still available through
+// the Property() method of the error.
+//		Labeled("Email Address", "email_address", foo.Email) // `Email Address: required`
+func Labeled(label, property string, value interface{}) error {
+	if err := V(value); err != nil {
+		return PropertyError{property: property, label: label, err: err}
 	}
 	return nil
 }
 
+// Validate a lazily computed property. An error from fn is attributed to the
+// property itself, otherwise the returned value is validated as with
+// Property.
+//		validator.Thunk("Bar", foo.LoadBar) // `Bar: connection refused` or `Bar.Baz: qux`
+func Thunk[T Interface](property string, fn func() (T, error)) error {
+	value, err := fn()
+	if err != nil {
+		return PropertyFunc(property, func() error { return err })
+	}
+	return Property(property, value)
+}
+
+// Implemented by the errors this package produces for invalid values,
+// PropertyError and Errors, to tell them apart from other failures.
+type ValidationError interface {
+	error
+	isValidationError()
+}
+
+// Whether err, or any error it wraps, is a ValidationError. A failure that
+// is not the value's fault, such as an allow-list that could not be loaded
+// or a canceled context, is not a validation error even when it is
+// attributed to a property, and neither is any error containing one.
+func IsValidationError(err error) bool {
+	var verr ValidationError
+	if !errors.As(err, &verr) {
+		return false
+	}
+	internal := false
+	eachLeaf(verr, func(_ string, leaf error) {
+		var ierr internalError
+		internal = internal || errors.As(leaf, &ierr)
+	})
+	return !internal
+}
+
+// A failure of the checks themselves rather than of the value checked,
+// which IsValidationError never reports as a validation error.
+type internalError struct {
+	err error
+}
+
+func (err internalError) Error() string { return err.err.Error() }
+
+func (err internalError) Unwrap() error { return err.err }
+
 // A validation error from by a (possibly nested) property.
+//
+// PropertyErrors constructed the same way from comparable leaf errors and
+// indices are equal under ==, so they can be compared directly in tests.
 type PropertyError struct {
 	property string
+	label    string
 	index    interface{}
 	err      error
 }
 
-// The validation error.
+func (err PropertyError) isValidationError() {}
+
+// The validation error. Wrapped errors are unwrapped fully, through nested
+// properties and any error with an Unwrap() error method, to the deepest
+// cause. An aggregate is returned as it is.
 func (err PropertyError) OriginatingError() error {
-	switch err.err.(type) {
-	case PropertyError:
-		return err.err.(PropertyError).OriginatingError()
+	cause := err.leaf()
+	for {
+		next := errors.Unwrap(cause)
+		if next == nil {
+			return cause
+		}
+		switch next.(type) {
+		case PropertyError:
+			cause = next.(PropertyError).leaf()
+		default:
+			cause = next
+		}
+	}
+}
+
+// The error at the end of the nested properties of err, which is rendered
+// as its message.
+func (err PropertyError) leaf() error {
+	for {
+		switch err.err.(type) {
+		case PropertyError:
+			err = err.err.(PropertyError)
+		default:
+			return err.err
+		}
 	}
-	return err.err
+}
+
+// The human readable label of the property, if any (see Labeled).
+func (err PropertyError) Label() string {
+	return err.label
 }
 
 // The name of the invalid property.
 func (err PropertyError) Property() string {
-	prefix := err.property
+	return err.path(false)
+}
+
+// True if any segment of the path of err is an index rather than a
+// property name, as for an element of a collection. Aggregates wrapped by
+// err are not searched, so call it on leaves, like those of Errors.Flatten.
+func (err PropertyError) HasIndex() bool {
+	for {
+		if err.index != nil {
+			return true
+		}
+		switch err.err.(type) {
+		case PropertyError:
+			err = err.err.(PropertyError)
+		default:
+			return false
+		}
+	}
+}
+
+// The full path of err, optionally using labels in place of property names.
+func (err PropertyError) path(labels bool) (path string) {
+	for {
+		path = err.appendTo(path, labels)
+		switch err.err.(type) {
+		case PropertyError:
+			err = err.err.(PropertyError)
+		default:
+			return path
+		}
+	}
+}
+
+// Append the property name and index of err, without any nested
+// properties, to path. Indices follow path directly while property names
+// are separated from it by a dot.
+func (err PropertyError) appendTo(path string, labels bool) string {
+	name := err.property
+	if labels && err.label != "" {
+		name = err.label
+	}
+	segment := name
 	if err.index != nil {
-		prefix = fmt.Sprintf("%s[%#v]", prefix, err.index)
+		segment += formatIndex(err.index)
 	}
-	switch err.err.(type) {
-	case PropertyError:
-		return fmt.Sprintf("%s.%s", prefix, err.property)
+	if path == "" || name == "" {
+		return path + segment
 	}
-	return prefix
+	return path + "." + segment
+}
+
+// An index type that controls its own rendering in property paths.
+//		type route struct{ method, path string }
+//		func (r route) Segment() string { return r.method + " " + r.path }
+//		validator.Index(route{"GET", "/users"}, handler) // `[GET /users]: ...`
+type PathSegment interface {
+	Segment() string
+}
+
+// A rune index, rendered as a quoted character rather than a number. Since
+// rune is an alias of int32 plain rune indices cannot be told apart from
+// numbers, so they must be converted to select this rendering.
+//		validator.Index(validator.RuneIndex('a'), v) // `['a']: ...`
+type RuneIndex rune
+
+func (r RuneIndex) Segment() string { return strconv.QuoteRune(rune(r)) }
+
+// Render an index as a bracketed path segment. Byte (uint8) indices are
+// rendered in decimal.
+func formatIndex(index interface{}) string {
+	switch index.(type) {
+	case uint8:
+		return fmt.Sprintf("[%d]", index)
+	case mapKey:
+		return "{" + index.(mapKey).Segment() + "}"
+	case PathSegment:
+		return "[" + index.(PathSegment).Segment() + "]"
+	}
+	return fmt.Sprintf("[%#v]", index)
 }
 
 // The invalid property concatenated with the validation error message.
+//
+// When the originating error is an aggregate, each of its members is
+// rendered with the full path, as by Errors. A leaf with an empty message
+// is rendered as "invalid".
 func (err PropertyError) Error() string {
-	prefix := err.property
-	if err.index != nil {
-		prefix = fmt.Sprintf("%s[%#v]", prefix, err.index)
-	}
 	switch err.err.(type) {
-	case PropertyError:
-		if err.err.(PropertyError).property == "" {
-			return fmt.Sprintf("%s%v", prefix, err.err)
+	case PropertyError, multiError, nil:
+	default:
+		if err.index == nil {
+			return err.shallowError()
 		}
-		return fmt.Sprintf("%s.%v", prefix, err.err)
 	}
-	return fmt.Sprintf("%s: %v", prefix, err.err)
+	leaf := err.leaf()
+	switch leaf.(type) {
+	case multiError:
+		return Errors(flatten(err)).Error()
+	}
+	return err.path(true) + ": " + leafMessage(leaf)
+}
+
+// The message of a leaf error, or "invalid" when its message is empty so
+// that a property is never rendered with a dangling colon.
+func leafMessage(leaf error) string {
+	if leaf == nil {
+		return "<nil>"
+	}
+	if msg := leaf.Error(); msg != "" {
+		return msg
+	}
+	return "invalid"
+}
+
+// Error() for the common case of a single property without an index,
+// built with a single allocation.
+func (err PropertyError) shallowError() string {
+	name, msg := err.property, leafMessage(err.err)
+	if err.label != "" {
+		name = err.label
+	}
+	var b strings.Builder
+	b.Grow(len(name) + 2 + len(msg))
+	b.WriteString(name)
+	b.WriteString(": ")
+	b.WriteString(msg)
+	return b.String()
+}
+
+// Same as Error(), so that %s and %v show the full message.
+func (err PropertyError) String() string {
+	return err.Error()
+}
+
+// A stable representation for %#v, showing the property path and the
+// message of the originating error.
+func (err PropertyError) GoString() string {
+	return fmt.Sprintf("validate.PropertyError{Property: %q, Err: %q}",
+		err.Property(), leafMessage(err.leaf()))
+}
+
+// Construct a PropertyError for an invalid property directly, as Property
+// would.
+func NewPropertyError(property string, err error) PropertyError {
+	return PropertyError{property: property, err: err}
+}
+
+// Nest inner beneath a property, as if inner was returned from a property
+// validated with Property.
+//		Nest("Bars", NestIndex(1, NewPropertyError("Baz", err))) // `Bars[1].Baz: ...`
+func Nest(outerProperty string, inner PropertyError) PropertyError {
+	return PropertyError{property: outerProperty, err: inner}
+}
+
+// Nest inner beneath an index, as if inner was returned from Index.
+func NestIndex(index interface{}, inner PropertyError) PropertyError {
+	return PropertyError{index: index, err: inner}
 }
 
 // Validate property element values (see Property).
@@ -144,10 +494,27 @@ func Index(index, value interface{}) error {
 	})
 }
 
-// Used for validating properties that are slices/maps
+// Validate an element of a multi-dimensional property, the first index
+// being outermost.
+//		validator.PropertyFunc("Grid", func() error {
+//			return validator.Indices([]interface{}{1, 2}, grid[1][2])
+//		}) // `Grid[1][2].Value: ...`
+func Indices(indices []interface{}, value interface{}) error {
+	err := V(value)
+	if err == nil {
+		return nil
+	}
+	for i := len(indices) - 1; i >= 0; i-- {
+		err = PropertyError{index: indices[i], err: err}
+	}
+	return err
+}
+
+// Used for validating properties that are slices/maps. Indices are rendered
+// with %#v unless they implement PathSegment.
 func IndexFunc(index interface{}, validate func() error) (err error) {
-	if err = validate(); err != nil {
-		return PropertyError{"", index, err}
+	if err = validate(); err != nil && err != Skipped {
+		return PropertyError{index: index, err: err}
 	}
 	return nil
 }
@@ -158,13 +525,46 @@ func IndexFunc(index interface{}, validate func() error) (err error) {
 //		Invalid("foo", "bar", "baz") // `Invalid foo bar: "baz"`
 //		...
 func Invalid(v ...interface{}) error {
-	prefix, size := "Invalid", len(v)
-	switch {
-	case size > 1:
-		prefix = fmt.Sprint(prefix, v[:size-1])
-		fallthrough
-	case size == 1:
-		return fmt.Errorf("%s: %#v", prefix, v[:size-1])
-	}
-	return errors.New(prefix)
+	size := len(v)
+	if size == 0 {
+		return InvalidError{}
+	}
+	words := make([]string, size-1)
+	for i, word := range v[:size-1] {
+		words[i] = fmt.Sprint(word)
+	}
+	return InvalidError{what: strings.Join(words, " "), value: v[size-1], hasValue: true}
+}
+
+// The error returned by Invalid.
+type InvalidError struct {
+	what     string // the words naming what the value is not, if any
+	reason   string // why the value is invalid, if not what it is not
+	value    interface{}
+	hasValue bool
+}
+
+func (err InvalidError) Error() string {
+	if !err.hasValue {
+		return err.SafeMessage()
+	}
+	return fmt.Sprintf("%s: %#v", err.SafeMessage(), err.value)
+}
+
+// The invalid value, if one was given.
+func (err InvalidError) Value() (interface{}, bool) {
+	return err.value, err.hasValue
+}
+
+// The message of err without the invalid value.
+//		Invalid("email", "bob@").(InvalidError).SafeMessage() // `Invalid email`
+func (err InvalidError) SafeMessage() string {
+	msg := "Invalid"
+	if err.what != "" {
+		msg += " " + err.what
+	}
+	if err.reason != "" {
+		msg += ": " + err.reason
+	}
+	return msg
 }
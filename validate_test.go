@@ -11,6 +11,9 @@ package validate
  */
 
 import (
+    "errors"
+    "fmt"
+    "reflect"
     "testing"
 )
 
@@ -19,3 +22,569 @@ func TestConfighelper(t *testing.T) {
 
 }
 
+type testQux int
+
+func (qux testQux) Validate() error {
+	if qux == 0 {
+		return errors.New("qux")
+	}
+	return nil
+}
+
+func TestLabeled(t *testing.T) {
+	err := Labeled("Email Address", "email_address", testQux(0))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if msg := err.Error(); msg != "Email Address: qux" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	perr := err.(PropertyError)
+	if prop := perr.Property(); prop != "email_address" {
+		t.Errorf("unexpected property %q", prop)
+	}
+	if label := perr.Label(); label != "Email Address" {
+		t.Errorf("unexpected label %q", label)
+	}
+	if err := Labeled("Email Address", "email_address", testQux(1)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+type testBar struct{ Baz testQux }
+
+func (bar testBar) Validate() error { return Property("Baz", bar.Baz) }
+
+// Reports every invalid element.
+type testBars []testBar
+
+func (bars testBars) Validate() error {
+	var errs []error
+	for i, bar := range bars {
+		if err := Index(i, bar); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func TestPropertyNested(t *testing.T) {
+	err := Property("Bars", testBars{testBar{1}, testBar{0}}).(PropertyError)
+	if prop := err.Property(); prop != "Bars" {
+		// the nested error is an aggregate, not a PropertyError
+		t.Errorf("unexpected property %q", prop)
+	}
+	if msg := err.Error(); msg != "Bars[1].Baz: qux" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	err = Property("Bar", testBar{0}).(PropertyError)
+	if prop := err.Property(); prop != "Bar.Baz" {
+		t.Errorf("unexpected property %q", prop)
+	}
+	err = PropertyFunc("Bars", func() error { return Index(1, testBar{0}) }).(PropertyError)
+	if prop := err.Property(); prop != "Bars[1].Baz" {
+		t.Errorf("unexpected property %q", prop)
+	}
+	if msg := err.Error(); msg != "Bars[1].Baz: qux" {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+type testRoute struct{ method, path string }
+
+func (r testRoute) Segment() string { return r.method + " " + r.path }
+
+func TestPathSegment(t *testing.T) {
+	err := PropertyFunc("Routes", func() error {
+		return Index(testRoute{"GET", "/users"}, testBar{0})
+	}).(PropertyError)
+	if msg := err.Error(); msg != "Routes[GET /users].Baz: qux" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if prop := err.Property(); prop != "Routes[GET /users].Baz" {
+		t.Errorf("unexpected property %q", prop)
+	}
+	if fields := InvalidFields(err); len(fields) != 1 || fields[0] != "Routes[GET /users].Baz" {
+		t.Errorf("unexpected fields %q", fields)
+	}
+}
+
+func TestNest(t *testing.T) {
+	organic := PropertyFunc("Bars", func() error { return Index(1, testBar{0}) })
+	manual := Nest("Bars", NestIndex(1, NewPropertyError("Baz", testQux(0).Validate())))
+	if organic.Error() != manual.Error() {
+		t.Errorf("expected %q got %q", organic, manual)
+	}
+	if prop := manual.Property(); prop != organic.(PropertyError).Property() {
+		t.Errorf("unexpected property %q", prop)
+	}
+	if manual.OriginatingError().Error() != "qux" {
+		t.Errorf("unexpected originating error %v", manual.OriginatingError())
+	}
+}
+
+type testPtr struct{ called bool }
+
+func (p *testPtr) Validate() error {
+	p.called = true
+	return errors.New("ptr")
+}
+
+func TestVNil(t *testing.T) {
+	if err := V(nil); err != nil {
+		t.Errorf("unexpected error for untyped nil %v", err)
+	}
+	if err := V((*testPtr)(nil)); err != nil {
+		t.Errorf("unexpected error for typed nil %v", err)
+	}
+	if err := V((*testBar)(nil)); err != nil {
+		t.Errorf("unexpected error for typed nil with value receiver %v", err)
+	}
+	if err := Property("Ptr", (*testPtr)(nil)); err != nil {
+		t.Errorf("unexpected error for nil property %v", err)
+	}
+	p := new(testPtr)
+	if err := V(p); err == nil || !p.called {
+		t.Errorf("expected Validate() to be called on a non-nil pointer")
+	}
+}
+
+type testCode string
+
+func (code testCode) Error() string { return string(code) }
+
+func TestPropertyErrorComparable(t *testing.T) {
+	a := Nest("Bars", NestIndex(1, NewPropertyError("Baz", testCode("qux"))))
+	b := PropertyFunc("Bars", func() error {
+		return IndexFunc(1, func() error { return Property("Baz", testCodeValue("qux")) })
+	})
+	if a != b {
+		t.Errorf("expected %#v == %#v", a, b)
+	}
+	if c := NestIndex(2, NewPropertyError("Baz", testCode("qux"))); a == c {
+		t.Errorf("expected %#v != %#v", a, c)
+	}
+	if s := fmt.Sprintf("%s|%v", a, a); s != "Bars[1].Baz: qux|Bars[1].Baz: qux" {
+		t.Errorf("unexpected string form %q", s)
+	}
+	if s := fmt.Sprintf("%#v", a); s != `validate.PropertyError{Property: "Bars[1].Baz", Err: "qux"}` {
+		t.Errorf("unexpected Go syntax form %q", s)
+	}
+	if s := fmt.Sprintf("%#v", NewPropertyError("Name", nil)); s != `validate.PropertyError{Property: "Name", Err: "<nil>"}` {
+		t.Errorf("unexpected Go syntax form of a nil leaf %q", s)
+	}
+}
+
+// Fails with a comparable error.
+type testCodeValue string
+
+func (v testCodeValue) Validate() error { return testCode(v) }
+
+func TestVDetailed(t *testing.T) {
+	for _, test := range []struct {
+		v         interface{}
+		validated bool
+		fails     bool
+	}{
+		{testQux(1), true, false},
+		{testQux(0), true, true},
+		{"not validatable", false, false},
+		{nil, false, false},
+		{(*testPtr)(nil), false, false},
+	} {
+		validated, err := VDetailed(test.v)
+		if validated != test.validated {
+			t.Errorf("VDetailed(%#v): expected validated %v", test.v, test.validated)
+		}
+		if (err != nil) != test.fails {
+			t.Errorf("VDetailed(%#v): unexpected error %v", test.v, err)
+		}
+	}
+}
+
+// Only validated when enabled.
+type testOptional struct {
+	enabled bool
+	qux     testQux
+}
+
+func (opt testOptional) Validate() error {
+	if !opt.enabled {
+		return Skipped
+	}
+	return Property("Qux", opt.qux)
+}
+
+func TestSkipped(t *testing.T) {
+	if err := PropertyFunc("Fax", func() error { return Skipped }); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := IndexFunc(0, func() error { return Skipped }); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := V(testOptional{}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if validated, err := VDetailed(testOptional{}); validated || err != nil {
+		t.Errorf("expected a skip, got %v, %v", validated, err)
+	}
+	if validated, err := VDetailed(testOptional{true, 1}); !validated || err != nil {
+		t.Errorf("expected a pass, got %v, %v", validated, err)
+	}
+	if validated, err := VDetailed(testOptional{true, 0}); !validated || err == nil {
+		t.Errorf("expected a failure, got %v, %v", validated, err)
+	}
+}
+
+// Build random PropertyError chains and check that the path and message
+// renderings agree with each other and with InvalidFields.
+func FuzzPropertyError(f *testing.F) {
+	f.Add([]byte{0, 1, 0}, "Bars", "qux")
+	f.Add([]byte{1, 1}, "", "")
+	f.Add([]byte{2, 0, 3}, "a.b", "x: y")
+	f.Fuzz(func(t *testing.T, ops []byte, name, msg string) {
+		leaf := testCode(msg)
+		err := NewPropertyError(name, leaf)
+		for i, op := range ops {
+			switch op % 4 {
+			case 0:
+				err = Nest(fmt.Sprint(name, i), err)
+			case 1:
+				err = NestIndex(int(op), err)
+			case 2:
+				err = NestIndex(fmt.Sprint(name, i), err)
+			case 3:
+				err = NestIndex(testRoute{name, msg}, err)
+			}
+		}
+		prop, rendered := err.Property(), err.Error()
+		if msg == "" {
+			msg = "invalid"
+		}
+		if expect := prop + ": " + msg; rendered != expect {
+			t.Fatalf("Error() %q does not match Property() %q", rendered, prop)
+		}
+		if fields := InvalidFields(err); len(fields) != 1 || fields[0] != prop {
+			t.Fatalf("InvalidFields() %q does not match Property() %q", fields, prop)
+		}
+		if err.OriginatingError() != leaf {
+			t.Fatalf("unexpected originating error %v", err.OriginatingError())
+		}
+	})
+}
+
+func TestPropertyErrorShallowAllocs(t *testing.T) {
+	err := Property("Name", testQux(0)).(PropertyError)
+	if msg := err.Error(); msg != "Name: qux" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if msg := Labeled("Full Name", "name", testQux(0)).Error(); msg != "Full Name: qux" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if msg := NewPropertyError("Name", nil).Error(); msg != "Name: <nil>" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if n := testing.AllocsPerRun(100, func() { _ = err.Error() }); n > 1 {
+		t.Errorf("shallow Error() made %v allocations", n)
+	}
+}
+
+func BenchmarkPropertyErrorShallow(b *testing.B) {
+	err := Property("Name", testQux(0)).(PropertyError)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.Error()
+	}
+}
+
+func BenchmarkPropertyErrorNested(b *testing.B) {
+	err := PropertyFunc("Bars", func() error { return Index(1, testBar{0}) }).(PropertyError)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.Error()
+	}
+}
+
+func TestPropertyRequired(t *testing.T) {
+	for _, value := range []interface{}{nil, "", 0, (*testBar)(nil), testBar{}} {
+		err := PropertyRequired("X", value)
+		if err == nil || err.Error() != "X: required" {
+			t.Errorf("%#v: unexpected error %v", value, err)
+		}
+	}
+	if err := PropertyRequired("X", testQux(0)); err == nil || err.Error() != "X: required" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := PropertyRequired("X", testBar{Baz: 0}); err == nil || err.(PropertyError).OriginatingError() != ErrRequired {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := PropertyRequired("X", &testBar{}); err == nil || err.Error() != "X.Baz: qux" {
+		t.Errorf("unexpected error for present but invalid value %v", err)
+	}
+	if err := PropertyRequired("X", testQux(1)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestAs(t *testing.T) {
+	notZero := func(v interface{}) error {
+		if v.(int) == 0 {
+			return errors.New("zero")
+		}
+		return nil
+	}
+	err := Property("Count", As(0, notZero))
+	if err == nil || err.Error() != "Count: zero" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Property("Count", As(1, notZero)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestIsValidationError(t *testing.T) {
+	perr := Property("Name", testQux(0))
+	for _, test := range []struct {
+		err    error
+		expect bool
+	}{
+		{perr, true},
+		{Errors{perr}, true},
+		{fmt.Errorf("loading: %w", perr), true},
+		{fmt.Errorf("loading: %w", Errors{perr}), true},
+		{errors.New("plain"), false},
+		{fmt.Errorf("loading: %w", errors.New("plain")), false},
+		{Errors{perr, PropertyFunc("Codes", func() error { return internalError{errors.New("down")} })}, false},
+		{fmt.Errorf("loading: %w", PropertyFunc("Codes", func() error { return internalError{errors.New("down")} })), false},
+		{nil, false},
+	} {
+		if is := IsValidationError(test.err); is != test.expect {
+			t.Errorf("IsValidationError(%v): expected %v", test.err, test.expect)
+		}
+	}
+}
+
+func TestIndexRuneByte(t *testing.T) {
+	err := PropertyFunc("Text", func() error { return Index(RuneIndex('A'), testQux(0)) })
+	if msg := err.Error(); msg != "Text['A']: qux" {
+		t.Errorf("unexpected rune index message %q", msg)
+	}
+	err = PropertyFunc("Data", func() error { return Index(byte(0x41), testQux(0)) })
+	if msg := err.Error(); msg != "Data[65]: qux" {
+		t.Errorf("unexpected byte index message %q", msg)
+	}
+}
+
+func TestMustValid(t *testing.T) {
+	if bar := MustValid(testBar{2}); bar.Baz != 2 {
+		t.Errorf("unexpected value %v", bar)
+	}
+	defer func() {
+		perr, ok := recover().(PropertyError)
+		if !ok || perr.Error() != "Baz: qux" {
+			t.Errorf("unexpected panic value %#v", perr)
+		}
+	}()
+	MustValid(testBar{0})
+	t.Errorf("expected a panic")
+}
+
+type testRows []testBar
+
+func (rows testRows) ValidateElements() error {
+	for i, row := range rows {
+		if err := Index(i, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestCollectionValidator(t *testing.T) {
+	err := Property("Rows", testRows{{1}, {0}, {3}})
+	if err == nil || err.Error() != "Rows[1].Baz: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if validated, err := VDetailed(testRows{{1}}); !validated || err != nil {
+		t.Errorf("unexpected result %v, %v", validated, err)
+	}
+	if validated, err := VDetailed((*testRows)(nil)); validated || err != nil {
+		t.Errorf("unexpected result for nil pointer %v, %v", validated, err)
+	}
+}
+
+type testCell struct{ Value testQux }
+
+func (cell testCell) Validate() error { return Property("Value", cell.Value) }
+
+func TestIndices(t *testing.T) {
+	grid := [][]testCell{{{1}, {1}, {1}}, {{1}, {1}, {0}}}
+	err := PropertyFunc("Grid", func() error {
+		for i, row := range grid {
+			for j, cell := range row {
+				if err := Indices([]interface{}{i, j}, cell); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "Grid[1][2].Value: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if prop := err.(PropertyError).Property(); prop != "Grid[1][2].Value" {
+		t.Errorf("unexpected property %q", prop)
+	}
+	if err := Indices(nil, testQux(0)); err == nil || err.Error() != "qux" {
+		t.Errorf("unexpected error without indices %v", err)
+	}
+}
+
+func TestEmptyLeafMessage(t *testing.T) {
+	if msg := NewPropertyError("Field", errors.New("")).Error(); msg != "Field: invalid" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	err := Nest("Outer", NestIndex(2, NewPropertyError("Field", errors.New(""))))
+	if msg := err.Error(); msg != "Outer[2].Field: invalid" {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
+
+func TestHasIndex(t *testing.T) {
+	if err := Property("Bar", testBar{0}).(PropertyError); err.HasIndex() {
+		t.Errorf("%v: unexpected index", err)
+	}
+	leaves := Errors{Property("Bars", testBars{testBar{1}, testBar{0}})}.Flatten()
+	if err := leaves[0].(PropertyError); !err.HasIndex() {
+		t.Errorf("%v: expected index", err)
+	}
+	if err := Nest("Grid", Index(2, testQux(0)).(PropertyError)); !err.HasIndex() {
+		t.Errorf("%v: expected index", err)
+	}
+}
+
+func TestThunk(t *testing.T) {
+	for _, test := range []struct {
+		fn  func() (testBar, error)
+		msg string
+	}{
+		{func() (testBar, error) { return testBar{1}, nil }, ""},
+		{func() (testBar, error) { return testBar{0}, nil }, "Bar.Baz: qux"},
+		{func() (testBar, error) { return testBar{1}, errors.New("connection refused") }, "Bar: connection refused"},
+	} {
+		err := Thunk("Bar", test.fn)
+		if test.msg == "" {
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.msg {
+			t.Errorf("expected %q got %v", test.msg, err)
+		}
+	}
+}
+
+func TestVIn(t *testing.T) {
+	err := VIn("while loading user config", testBar{0})
+	if err == nil || err.Error() != "while loading user config: Baz: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+	var perr PropertyError
+	if !errors.As(err, &perr) || perr.Property() != "Baz" {
+		t.Errorf("unexpected property error %#v", perr)
+	}
+	if !IsValidationError(err) {
+		t.Errorf("expected a validation error")
+	}
+	if err := VIn("while loading user config", testBar{1}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err = VIn("while loading", testBars{testBar{1}, testBar{0}})
+	if fields := InvalidFields(VIn("while loading", testBar{0})); !reflect.DeepEqual(fields, []string{"Baz"}) {
+		t.Errorf("unexpected fields %q", fields)
+	}
+	if ptrs := PointerErrors(err); !reflect.DeepEqual(ptrs, map[string]string{"/1/Baz": "qux"}) {
+		t.Errorf("unexpected pointers %v", ptrs)
+	}
+	if path := FirstPath(err); path != "[1].Baz" {
+		t.Errorf("unexpected path %q", path)
+	}
+	if tree := Tree(err); len(tree.Children) != 1 || tree.Children[0].Segment != "[1]" {
+		t.Errorf("unexpected tree %#v", tree)
+	}
+	mapped := RewriteLeaves(err, func(path string, leaf error) error { return errors.New(path) })
+	if mapped == nil || mapped.Error() != "while loading: [1].Baz: [1].Baz" {
+		t.Errorf("unexpected error %v", mapped)
+	}
+
+	errs := Errors{VIn("loading", testBars{testBar{0}, testBar{0}})}
+	if errs.Len() != 2 || errs.DistinctFields() != 2 {
+		t.Errorf("unexpected counts %d %d", errs.Len(), errs.DistinctFields())
+	}
+	if UnwrapSingle(errs) == nil || UnwrapSingle(errs).Error() != errs.Error() {
+		t.Errorf("unexpected single %v", UnwrapSingle(errs))
+	}
+	roots := ByRootField(errs)
+	if len(roots) != 2 || roots["[0]"] == nil || roots["[0]"].Error() != "loading: Baz: qux" {
+		t.Errorf("unexpected roots %v", roots)
+	}
+	if validity := FieldValidity(testBarHolder{Bar: testBar{0}}); validity["Bar"] {
+		t.Errorf("unexpected validity %v", validity)
+	}
+	prefixed := Prefix("Cfg", VIn("x", testBar{0}))
+	if prefixed == nil || prefixed.Error() != "x: Cfg.Baz: qux" {
+		t.Errorf("unexpected prefixed error %v", prefixed)
+	}
+	if !errors.As(prefixed, &perr) || perr.Property() != "Cfg.Baz" {
+		t.Errorf("unexpected prefixed property %#v", perr)
+	}
+	if fields := InvalidFields(prefixed); !reflect.DeepEqual(fields, []string{"Cfg.Baz"}) {
+		t.Errorf("unexpected prefixed fields %q", fields)
+	}
+}
+
+type testBarHolder struct {
+	Bar testBar
+}
+
+func (h testBarHolder) Validate() error {
+	if err := Property("Bar", h.Bar); err != nil {
+		return contextError{"loading", err}
+	}
+	return nil
+}
+
+func TestWithIndex(t *testing.T) {
+	err := V(WithIndex(3, testBar{0}))
+	if err == nil || err.Error() != "[3].Baz: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Property("Bars", WithIndex(3, testBar{0})); err == nil || err.Error() != "Bars[3].Baz: qux" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := V(WithIndex(3, testBar{1})); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// A vendor error wrapping its cause.
+type testWrapped struct{ err error }
+
+func (w testWrapped) Error() string { return "vendor: " + w.err.Error() }
+func (w testWrapped) Unwrap() error { return w.err }
+
+func TestOriginatingErrorUnwrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := NewPropertyError("Avatar", testWrapped{fmt.Errorf("fetch: %w", cause)})
+	if err.OriginatingError() != cause {
+		t.Errorf("expected %v got %v", cause, err.OriginatingError())
+	}
+	if err.Error() != "Avatar: vendor: fetch: connection refused" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+	nested := Nest("User", NewPropertyError("Avatar", testWrapped{cause}))
+	if nested.OriginatingError() != cause {
+		t.Errorf("expected %v got %v", cause, nested.OriginatingError())
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package validatetest provides assertions for testing validation written
+// with package validate. It is kept apart so that programs importing
+// validate do not link the testing package.
+package validatetest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	validate "github.com/bmatsuo/go-validate"
+)
+
+var (
+	interfaceType  = reflect.TypeOf((*validate.Interface)(nil)).Elem()
+	collectionType = reflect.TypeOf((*validate.CollectionValidator)(nil)).Elem()
+)
+
+// Fail t for each exported field of the struct (or struct pointer) v that
+// is not covered by validation, catching new fields that were never wired
+// into Validate. A field is covered if its type, or a pointer to it,
+// implements validate.Interface or validate.CollectionValidator, if it is a slice, array, map
+// or pointer of a covered type, or if its name is listed in except.
+//
+//	validatetest.AssertAllFieldsValidatable(t, Order{}, "Notes")
+func AssertAllFieldsValidatable(t testing.TB, v interface{}, except ...string) {
+	t.Helper()
+	typ := reflect.TypeOf(v)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		t.Fatalf("validatetest: AssertAllFieldsValidatable of non-struct type %T", v)
+		return
+	}
+	excepted := make(map[string]bool, len(except))
+	for _, name := range except {
+		excepted[name] = true
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" || excepted[field.Name] {
+			continue
+		}
+		if !validatable(field.Type) {
+			t.Errorf("%s.%s: type %v is not validated", typ.Name(), field.Name, field.Type)
+		}
+	}
+}
+
+func validatable(typ reflect.Type) bool {
+	for _, t := range []reflect.Type{typ, reflect.PointerTo(typ)} {
+		if t.Implements(interfaceType) || t.Implements(collectionType) {
+			return true
+		}
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Ptr:
+		return validatable(typ.Elem())
+	}
+	return false
+}
+
+// Fail t unless the leaves of err, each rendered as "path: message" like
+// validate.Errors does, are exactly expected in any order. The failure
+// lists the expected leaves that are missing and the actual leaves that
+// are not expected.
+//
+//	validatetest.AssertInvalid(t, err, "Bars[1].Baz: qux", "Email: required")
+func AssertInvalid(t testing.TB, err error, expected ...string) {
+	t.Helper()
+	var actual []string
+	for _, v := range validate.FieldViolations(err) {
+		if v.Field == "" {
+			actual = append(actual, v.Description)
+		} else {
+			actual = append(actual, v.Field+": "+v.Description)
+		}
+	}
+	if diff := leafDiff(expected, actual); diff != "" {
+		t.Errorf("validatetest: unexpected leaves:\n%s", diff)
+	}
+}
+
+// The leaves only in expected prefixed "- " and those only in actual
+// prefixed "+ ", one per line, or "" if they hold the same leaves.
+func leafDiff(expected, actual []string) string {
+	counts := make(map[string]int)
+	for _, leaf := range actual {
+		counts[leaf]++
+	}
+	var missing []string
+	for _, leaf := range expected {
+		if counts[leaf] > 0 {
+			counts[leaf]--
+		} else {
+			missing = append(missing, leaf)
+		}
+	}
+	var b strings.Builder
+	for _, leaf := range missing {
+		fmt.Fprintf(&b, "- %s\n", leaf)
+	}
+	for _, leaf := range actual {
+		if counts[leaf] > 0 {
+			counts[leaf]--
+			fmt.Fprintf(&b, "+ %s\n", leaf)
+		}
+	}
+	return b.String()
+}
+
+// Fail t unless normalizing v (see validate.Normalize) is idempotent and the result
+// is valid. v, a pointer, is normalized once, copied, and normalized again;
+// the second normalization must leave v deeply equal to the copy. The copy
+// is shallow, so changes made in place to shared slices and maps are not
+// detected. Finally validate.V(v) must pass.
+//
+//	validatetest.AssertNormalizedIdempotent(t, &Signup{Email: " Bob@Example.com "})
+func AssertNormalizedIdempotent(t testing.TB, v interface{}) {
+	t.Helper()
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		t.Fatalf("validatetest: AssertNormalizedIdempotent of non-pointer type %T", v)
+		return
+	}
+	validate.Normalize(v)
+	once := val.Elem().Interface()
+	validate.Normalize(v)
+	if twice := val.Elem().Interface(); !reflect.DeepEqual(once, twice) {
+		t.Errorf("validatetest: normalization is not idempotent:\nonce:  %#v\ntwice: %#v", once, twice)
+	}
+	if err := validate.V(v); err != nil {
+		t.Errorf("validatetest: invalid after normalization: %v", err)
+	}
+}
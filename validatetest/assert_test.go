@@ -0,0 +1,148 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validatetest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	validate "github.com/bmatsuo/go-validate"
+)
+
+type testQux int
+
+func (qux testQux) Validate() error {
+	if qux == 0 {
+		return errors.New("qux")
+	}
+	return nil
+}
+
+type testBar struct{ Baz testQux }
+
+func (bar testBar) Validate() error { return validate.Property("Baz", bar.Baz) }
+
+// Reports every invalid element.
+type testBars []testBar
+
+func (bars testBars) Validate() error {
+	var errs validate.Errors
+	for i, bar := range bars {
+		if err := validate.Index(i, bar); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs.Err()
+}
+
+type testLogin struct{ Email string }
+
+func (l *testLogin) Normalize() { l.Email = strings.ToLower(l.Email) }
+
+func (l *testLogin) Validate() error {
+	return validate.PropertyFunc("Email", func() error {
+		if l.Email != strings.ToLower(l.Email) {
+			return validate.Invalid("not lower case", l.Email)
+		}
+		return nil
+	})
+}
+
+// Records failures instead of reporting them.
+type testTB struct {
+	testing.TB
+	failures []string
+}
+
+func (t *testTB) Helper() {}
+
+func (t *testTB) Errorf(format string, v ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, v...))
+}
+
+func (t *testTB) Fatalf(format string, v ...interface{}) {
+	t.Errorf(format, v...)
+}
+
+type testCart struct {
+	Bar   testBar
+	Bars  testBars
+	Ptr   *testBar
+	Quxes map[string]testQux
+	Notes string
+	id    int
+}
+
+func TestAssertAllFieldsValidatable(t *testing.T) {
+	tb := &testTB{}
+	AssertAllFieldsValidatable(tb, testCart{})
+	if len(tb.failures) != 1 || tb.failures[0] != "testCart.Notes: type string is not validated" {
+		t.Errorf("unexpected failures %q", tb.failures)
+	}
+
+	tb = &testTB{}
+	AssertAllFieldsValidatable(tb, &testCart{}, "Notes")
+	if len(tb.failures) != 0 {
+		t.Errorf("unexpected failures %q", tb.failures)
+	}
+
+	tb = &testTB{}
+	AssertAllFieldsValidatable(tb, 1)
+	if len(tb.failures) != 1 {
+		t.Errorf("expected failure for non-struct")
+	}
+}
+
+func TestAssertInvalid(t *testing.T) {
+	err := validate.Errors{
+		validate.Property("Bars", testBars{testBar{0}, testBar{1}, testBar{0}}),
+		validate.NewPropertyError("Email", errors.New("required")),
+	}
+	tb := &testTB{}
+	AssertInvalid(tb, err, "Email: required", "Bars[2].Baz: qux", "Bars[0].Baz: qux")
+	if len(tb.failures) != 0 {
+		t.Errorf("unexpected failures %q", tb.failures)
+	}
+
+	tb = &testTB{}
+	AssertInvalid(tb, err, "Bars[0].Baz: qux", "Bars[1].Baz: qux", "Email: required")
+	expect := "validatetest: unexpected leaves:\n- Bars[1].Baz: qux\n+ Bars[2].Baz: qux\n"
+	if len(tb.failures) != 1 || tb.failures[0] != expect {
+		t.Errorf("unexpected failures %q", tb.failures)
+	}
+
+	tb = &testTB{}
+	AssertInvalid(tb, nil)
+	if len(tb.failures) != 0 {
+		t.Errorf("unexpected failures %q", tb.failures)
+	}
+}
+
+// Appends a suffix every time it is normalized.
+type testSuffixed struct{ Name string }
+
+func (s *testSuffixed) Normalize() { s.Name += "!" }
+
+func TestAssertNormalizedIdempotent(t *testing.T) {
+	tb := &testTB{}
+	AssertNormalizedIdempotent(tb, &testLogin{"Bob@Example.com"})
+	if len(tb.failures) != 0 {
+		t.Errorf("unexpected failures %q", tb.failures)
+	}
+
+	tb = &testTB{}
+	AssertNormalizedIdempotent(tb, &testSuffixed{"a"})
+	if len(tb.failures) != 1 || !strings.HasPrefix(tb.failures[0], "validatetest: normalization is not idempotent") {
+		t.Errorf("unexpected failures %q", tb.failures)
+	}
+
+	tb = &testTB{}
+	AssertNormalizedIdempotent(tb, testLogin{})
+	if len(tb.failures) != 1 {
+		t.Errorf("expected failure for non-pointer")
+	}
+}
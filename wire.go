@@ -0,0 +1,259 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// The version byte that begins every encoding made by MarshalErrors.
+const wireVersion = 1
+
+// The kinds of index in an encoded path segment. A map key index (see
+// Entries) has wireMapKey added to the kind of its key.
+const (
+	wireNoIndex = iota
+	wireInt
+	wireString
+	wireText
+	wireMapKey = 0x10
+)
+
+// Encode the leaves of err compactly, for returning validation results
+// from one service to another. The path, message, code (see Coder) and
+// severity (see Warn) of every leaf are kept; other detail, such as the
+// invalid value of an InvalidError, is not. Paths keep their structure, so
+// the decoded error has the same fields, indices and map keys. A nil err
+// encodes as no leaves.
+//
+// The encoding is a version byte, currently 1, then the number of leaves as
+// a uvarint. Each leaf is a flags byte (1 for a warning), the number of
+// segments of its path as a uvarint, the segments, and then its message and
+// code. A segment is its property name and label followed by a kind byte
+// and the index: nothing, a varint, or a string, for no index, an integer
+// or a string; any other index is its rendering in paths followed by its
+// JSON Pointer token. Strings are a uvarint
+// length and that many bytes. The format is stable: a change to it gets a
+// new version, and UnmarshalErrors keeps decoding the versions before it.
+func MarshalErrors(err error) ([]byte, error) {
+	var body []byte
+	n := 0
+	walkSegments(nil, err, func(path []PropertyError, leaf error) {
+		n++
+		var flag byte
+		if IsWarning(leaf) {
+			flag = 1
+		}
+		code := codeOf(leaf)
+		body = append(body, flag)
+		body = binary.AppendUvarint(body, uint64(len(path)))
+		for _, segment := range path {
+			body = appendWireString(body, segment.property)
+			body = appendWireString(body, segment.label)
+			body = appendWireIndex(body, segment.index)
+		}
+		body = appendWireString(body, leaf.Error())
+		body = appendWireString(body, code)
+	})
+	data := binary.AppendUvarint([]byte{wireVersion}, uint64(n))
+	return append(data, body...), nil
+}
+
+// Call fn with the chain of PropertyErrors above every leaf of err.
+func walkSegments(path []PropertyError, err error, fn func(path []PropertyError, leaf error)) {
+	switch e := err.(type) {
+	case nil:
+	case PropertyError:
+		walkSegments(append(path[:len(path):len(path)], e), e.err, fn)
+	case contextError:
+		walkSegments(path, e.err, fn)
+	case multiError:
+		for _, child := range e.Unwrap() {
+			walkSegments(path, child, fn)
+		}
+	default:
+		fn(path, err)
+	}
+}
+
+func appendWireString(data []byte, s string) []byte {
+	data = binary.AppendUvarint(data, uint64(len(s)))
+	return append(data, s...)
+}
+
+func appendWireIndex(data []byte, index interface{}) []byte {
+	if index == nil {
+		return append(data, wireNoIndex)
+	}
+	kind, render := byte(0), ""
+	switch index.(type) {
+	case mapKey:
+		kind, index = wireMapKey, index.(mapKey).key
+		render = fmt.Sprintf("%#v", index)
+	case PathSegment:
+		render = index.(PathSegment).Segment()
+	default:
+		render = fmt.Sprintf("%#v", index)
+	}
+	if _, ok := index.(PathSegment); !ok {
+		val := reflect.ValueOf(index)
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return binary.AppendVarint(append(data, kind|wireInt), val.Int())
+		case reflect.Uint8:
+			if kind != wireMapKey {
+				return binary.AppendVarint(append(data, kind|wireInt), int64(val.Uint()))
+			}
+		case reflect.String:
+			return appendWireString(append(data, kind|wireString), val.String())
+		}
+	}
+	data = appendWireString(append(data, kind|wireText), render)
+	return appendWireString(data, pointerToken(index))
+}
+
+// Decode the result of MarshalErrors into an error that renders, and
+// reports paths, codes and warnings, like the one encoded. Each leaf is
+// nested in one PropertyError per segment of its path, so ByRootField,
+// PointerErrors and the other functions walking paths see the original
+// structure. The first result is nil if no leaves were encoded; the second
+// is non-nil if data is not a valid encoding.
+//
+//	verr, err := validate.UnmarshalErrors(body)
+func UnmarshalErrors(data []byte) (error, error) {
+	if len(data) == 0 || data[0] != wireVersion {
+		return nil, errInvalidWire
+	}
+	d := wireDecoder{data: data[1:]}
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		return nil, errInvalidWire
+	}
+	errs := make(Errors, 0, n)
+	for ; n > 0 && !d.failed; n-- {
+		flag := d.byte()
+		segments := d.uvarint()
+		if segments > uint64(len(d.data)) {
+			return nil, errInvalidWire
+		}
+		path := make([]PropertyError, segments)
+		for i := range path {
+			path[i] = PropertyError{property: d.string(), label: d.string(), index: d.index()}
+		}
+		var leaf error = wireError{d.string(), d.string()}
+		if flag&1 != 0 {
+			leaf = Warn(leaf)
+		}
+		for i := len(path) - 1; i >= 0; i-- {
+			path[i].err = leaf
+			leaf = path[i]
+		}
+		errs = append(errs, leaf)
+	}
+	if d.failed || len(d.data) != 0 {
+		return nil, errInvalidWire
+	}
+	return errs.Err(), nil
+}
+
+var errInvalidWire = errors.New("validate: invalid error encoding")
+
+// Reads an encoding made by MarshalErrors. Once a read fails, failed is
+// set and later reads return zero values.
+type wireDecoder struct {
+	data   []byte
+	failed bool
+}
+
+func (d *wireDecoder) byte() byte {
+	if d.failed || len(d.data) == 0 {
+		d.failed = true
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *wireDecoder) uvarint() uint64 {
+	v, size := binary.Uvarint(d.data)
+	if d.failed || size <= 0 {
+		d.failed = true
+		return 0
+	}
+	d.data = d.data[size:]
+	return v
+}
+
+func (d *wireDecoder) varint() int64 {
+	v, size := binary.Varint(d.data)
+	if d.failed || size <= 0 {
+		d.failed = true
+		return 0
+	}
+	d.data = d.data[size:]
+	return v
+}
+
+func (d *wireDecoder) string() string {
+	n := d.uvarint()
+	if d.failed || n > uint64(len(d.data)) {
+		d.failed = true
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+func (d *wireDecoder) index() interface{} {
+	kind := d.byte()
+	var index interface{}
+	switch kind &^ wireMapKey {
+	case wireNoIndex:
+		if kind != wireNoIndex {
+			d.failed = true
+		}
+		return nil
+	case wireInt:
+		index = int(d.varint())
+	case wireString:
+		index = d.string()
+	case wireText:
+		index = wireSegment{d.string(), d.string()}
+	default:
+		d.failed = true
+		return nil
+	}
+	if kind&wireMapKey != 0 {
+		return mapKey{index}
+	}
+	return index
+}
+
+// A leaf decoded by UnmarshalErrors.
+type wireError struct {
+	msg  string
+	code string
+}
+
+func (err wireError) Error() string { return err.msg }
+func (err wireError) Code() string  { return err.code }
+
+func (err wireError) isValidationError() {}
+
+// An index decoded by UnmarshalErrors from its rendering in paths and its
+// JSON Pointer token. It renders the same both as an index and as a map
+// key.
+type wireSegment struct {
+	render string
+	token  string
+}
+
+func (s wireSegment) Segment() string  { return s.render }
+func (s wireSegment) GoString() string { return s.render }
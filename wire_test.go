@@ -0,0 +1,78 @@
+// Copyright 2012, Bryan Matsuo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalErrors(t *testing.T) {
+	err := Errors{
+		Property("Bars", testBars{testBar{0}, testBar{1}, testBar{0}}),
+		NewPropertyError("Nickname", Warn(errors.New("will be truncated"))),
+		NewPropertyError("Email", recodedError{errors.New("bad domain"), "email"}),
+		errors.New("plain"),
+		NewPropertyError("Bio", fmt.Errorf("too long: %w", Warn(errors.New("over 80")))),
+		Entries("Env", map[interface{}]int{"a/b": -1, 7: -1, 2.5: -1}, func(k interface{}) error {
+			return errors.New("bad key")
+		}, nil),
+		Index(RuneIndex('x'), testQux(0)),
+		Index(uint16(3), testQux(0)),
+	}
+	data, merr := MarshalErrors(err)
+	if merr != nil {
+		t.Fatal(merr)
+	}
+	decoded, uerr := UnmarshalErrors(data)
+	if uerr != nil {
+		t.Fatal(uerr)
+	}
+	if decoded == nil || decoded.Error() != err.Error() {
+		t.Errorf("expected %q got %v", err, decoded)
+	}
+	if !IsValidationError(decoded) {
+		t.Errorf("decoded error is not a validation error")
+	}
+	if p, q := PointerErrors(decoded), PointerErrors(err); !reflect.DeepEqual(p, q) {
+		t.Errorf("expected pointers %v got %v", q, p)
+	}
+	if p, q := InvalidFields(decoded), InvalidFields(err); !reflect.DeepEqual(p, q) {
+		t.Errorf("expected fields %q got %q", q, p)
+	}
+	roots := ByRootField(decoded)
+	if roots["Bars"] == nil || roots["Bars"].Error() != "[0].Baz: qux; [2].Baz: qux" {
+		t.Errorf("unexpected groups %v", roots)
+	}
+	errs, _ := decoded.(Errors)
+	if len(errs) != 11 {
+		t.Fatalf("expected 11 leaves got %#v", decoded)
+	}
+	if MaxSeverity(errs[5]) != SeverityWarning || errs[5].Error() != "Bio: too long: over 80" {
+		t.Errorf("wrapped warning not preserved: %v", errs[5])
+	}
+	if MaxSeverity(errs[2]) != SeverityWarning || MaxSeverity(errs[0]) != SeverityError {
+		t.Errorf("severities not preserved")
+	}
+	if codeOf(errs[3].(PropertyError).OriginatingError()) != "email" || codeOf(errs[0].(PropertyError).OriginatingError()) != DefaultCode {
+		t.Errorf("codes not preserved")
+	}
+	if again, _ := MarshalErrors(decoded); !bytes.Equal(again, data) {
+		t.Errorf("encoding changed on a round trip")
+	}
+
+	data, _ = MarshalErrors(nil)
+	if decoded, uerr := UnmarshalErrors(data); decoded != nil || uerr != nil {
+		t.Errorf("unexpected result %v %v", decoded, uerr)
+	}
+	for _, bad := range [][]byte{nil, {2, 0}, {1}, {1, 1, 0, 5, 'a'}, append(data, 0)} {
+		if _, uerr := UnmarshalErrors(bad); uerr == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}